@@ -0,0 +1,371 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	goviteIgnoreFile = ".goviteignore"
+	gitIgnoreFile    = ".gitignore"
+)
+
+// defaultIgnorePatterns are left out of every module copy unless an ignore
+// file re-includes them with a negated pattern.
+var defaultIgnorePatterns = []string{".git/", "node_modules/"}
+
+// ignoreRule is one pattern line from an ignore file, following .gitignore
+// semantics.
+type ignoreRule struct {
+	// base is the directory holding the ignore file, relative to the module
+	// root in slash form, or "" for the root itself.
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// anchored patterns contain a slash and match from base; the rest match
+	// a name at any depth.
+	anchored bool
+}
+
+// ignoreMatcher decides which paths of a module tree are excluded. Rules are
+// collected from a .goviteignore in each directory or, when a directory has
+// none, its .gitignore. Later and deeper rules win, as in git.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+func newIgnoreMatcher() *ignoreMatcher {
+	m := &ignoreMatcher{}
+	for _, pattern := range defaultIgnorePatterns {
+		m.add("", pattern)
+	}
+	return m
+}
+
+// loadDir adds the rules of the ignore file in rel, a directory of the tree
+// rooted at root.
+func (m *ignoreMatcher) loadDir(root, rel string) error {
+	for _, name := range []string{goviteIgnoreFile, gitIgnoreFile} {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel), name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			m.add(rel, line)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (m *ignoreMatcher) add(base, line string) {
+	line = strings.TrimSuffix(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return
+	}
+	rule.segments = strings.Split(line, "/")
+	m.rules = append(m.rules, rule)
+}
+
+// ignored reports whether rel, a slash-separated path relative to the module
+// root, is excluded.
+func (m *ignoreMatcher) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.matches(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	parts := strings.Split(rel, "/")
+	if !r.anchored {
+		parts = parts[len(parts)-1:]
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments matches path segments against glob segments, where "**"
+// matches any number of segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return len(name) > 0
+		}
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], name[0])
+	return ok && matchSegments(pattern[1:], name[1:])
+}
+
+// walkModuleTree calls fn for every path under root that the ignore rules
+// keep, parents before children, with rel slash-separated and relative to
+// root. Symlinks are reported, never followed, so root should already have
+// its own symlinks resolved.
+func walkModuleTree(root string, fn func(rel string, info os.FileInfo) error) error {
+	m := newIgnoreMatcher()
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return m.loadDir(root, "")
+		}
+		if m.ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if err := m.loadDir(root, rel); err != nil {
+				return err
+			}
+		}
+		return fn(rel, info)
+	})
+}
+
+// symlinkTarget returns the target a copy of the symlink at rel should have:
+// the link's destination relative to the link itself. Links that lead out
+// of root are refused, since the copy would no longer be self-contained.
+func symlinkTarget(root, rel string) (string, error) {
+	link := filepath.Join(root, filepath.FromSlash(rel))
+	target, err := os.Readlink(link)
+	if err != nil {
+		return "", err
+	}
+	resolved := target
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(link), resolved)
+	}
+	inside, err := filepath.Rel(root, filepath.Clean(resolved))
+	if err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("symlink %s points outside the module (%s)", rel, target)
+	}
+	return filepath.Rel(filepath.Dir(link), filepath.Clean(resolved))
+}
+
+func copyDir(src, dst string) error {
+	return copyTree(src, dst)
+}
+
+// copyTree copies a module from src to dst, skipping ignored paths and
+// preserving permissions and modification times. Symlinks inside the module
+// are recreated; symlinks leading out of it are an error. Files are copied
+// in parallel, cloned where the filesystem supports it. Files are never
+// hardlinked: editing an installed module must not change the registry or
+// git cache it came from.
+func copyTree(src, dst string) error {
+	src, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, srcInfo.Mode().Perm()|0700); err != nil {
+		return err
+	}
+
+	type copiedDir struct {
+		path string
+		info os.FileInfo
+	}
+	dirs := []copiedDir{{dst, srcInfo}}
+	var files []string
+
+	err = walkModuleTree(src, func(rel string, info os.FileInfo) error {
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		switch {
+		case info.IsDir():
+			dirs = append(dirs, copiedDir{target, info})
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			linkTarget, err := symlinkTarget(src, rel)
+			if err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			return os.Symlink(linkTarget, target)
+		case info.Mode().IsRegular():
+			files = append(files, rel)
+		}
+		// Sockets, pipes and devices don't belong in a module
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := copyFiles(src, dst, files); err != nil {
+		return err
+	}
+
+	// Writing into a directory changes its modification time, so directories
+	// are finished last, deepest first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].info.Mode().Perm()); err != nil {
+			return err
+		}
+		modTime := dirs[i].info.ModTime()
+		if err := os.Chtimes(dirs[i].path, modTime, modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFiles copies files, relative to src, into dst using one worker per CPU.
+func copyFiles(src, dst string, files []string) error {
+	workers := runtime.NumCPU()
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	jobs := make(chan string)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range jobs {
+				from := filepath.Join(src, filepath.FromSlash(rel))
+				to := filepath.Join(dst, filepath.FromSlash(rel))
+				if err := copyTreeFile(from, to); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	for _, rel := range files {
+		if len(errs) > 0 {
+			break
+		}
+		jobs <- rel
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+func copyTreeFile(src, dst string) error {
+	// Never write through an existing file: it may be a hardlink into a
+	// cache, left by an earlier install
+	if err := removeExisting(dst); err != nil {
+		return err
+	}
+	return copyFile(src, dst)
+}
+
+func removeExisting(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// copyFile copies src to dst with its permissions and modification time,
+// cloning the data instead when the filesystem supports it.
+func copyFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if err := reflink(dstFile, srcFile); err != nil {
+		if _, err := io.Copy(dstFile, srcFile); err != nil {
+			dstFile.Close()
+			return err
+		}
+	}
+	if err := dstFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(dst, srcInfo.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// reflink is only implemented on Linux; elsewhere files are always copied.
+func reflink(dst, src *os.File) error {
+	return errors.ErrUnsupported
+}
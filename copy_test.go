@@ -0,0 +1,185 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := newIgnoreMatcher()
+	for _, line := range []string{
+		"# build outputs",
+		"dist/",
+		"*.log",
+		"!keep.log",
+		"/coverage",
+		"docs/**/*.tmp",
+		`\#notes`,
+	} {
+		m.add("", line)
+	}
+	m.add("sub", "local.txt")
+
+	cases := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{".git", true, true},
+		{"pkg/node_modules", true, true},
+		{"dist", true, true},
+		{"dist", false, false},
+		{"src/dist", true, true},
+		{"debug.log", false, true},
+		{"deep/trace.log", false, true},
+		{"keep.log", false, false},
+		{"coverage", false, true},
+		{"src/coverage", false, false},
+		{"docs/a/b/c.tmp", false, true},
+		{"docs/c.tmp", false, true},
+		{"c.tmp", false, false},
+		{"#notes", false, true},
+		{"sub/local.txt", false, true},
+		{"local.txt", false, false},
+		{"index.js", false, false},
+	}
+	for _, c := range cases {
+		if got := m.ignored(c.path, c.isDir); got != c.ignored {
+			t.Fatalf("ignored(%q, dir=%v) = %v, expected %v", c.path, c.isDir, got, c.ignored)
+		}
+	}
+}
+
+func TestCopyTreeIgnores(t *testing.T) {
+	src := t.TempDir()
+	for _, dir := range []string{".git", "node_modules/left-pad", "dist", "lib/tmp"} {
+		os.MkdirAll(filepath.Join(src, dir), 0755)
+	}
+	files := map[string]string{
+		".git/HEAD":                        "ref",
+		"node_modules/left-pad/a.js":       "pad",
+		"dist/bundle.js":                   "bundle",
+		"lib/tmp/scratch":                  "scratch",
+		"lib/index.js":                     "lib",
+		"index.js":                         "index",
+		".gitignore":                       "dist/\n",
+		filepath.Join("lib", ".gitignore"): "tmp/\n",
+	}
+	for file, content := range files {
+		os.WriteFile(filepath.Join(src, file), []byte(content), 0644)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	for _, kept := range []string{"index.js", "lib/index.js", ".gitignore"} {
+		if _, err := os.Stat(filepath.Join(dst, kept)); err != nil {
+			t.Fatalf("Expected %s to be copied", kept)
+		}
+	}
+	for _, skipped := range []string{".git", "node_modules", "dist", "lib/tmp"} {
+		if _, err := os.Stat(filepath.Join(dst, skipped)); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s to be skipped", skipped)
+		}
+	}
+
+	// .goviteignore takes precedence over .gitignore
+	os.WriteFile(filepath.Join(src, goviteIgnoreFile), []byte("!node_modules/\n*.md\n"), 0644)
+	os.WriteFile(filepath.Join(src, "README.md"), []byte("readme"), 0644)
+	dst = filepath.Join(t.TempDir(), "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	for _, kept := range []string{"dist/bundle.js", "node_modules/left-pad/a.js"} {
+		if _, err := os.Stat(filepath.Join(dst, kept)); err != nil {
+			t.Fatalf("Expected %s to be copied", kept)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "README.md")); !os.IsNotExist(err) {
+		t.Fatal("Expected README.md to be skipped")
+	}
+}
+
+func TestCopyTreeSymlinks(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "lib"), 0755)
+	os.WriteFile(filepath.Join(src, "lib", "index.js"), []byte("lib"), 0644)
+	os.Symlink("lib/index.js", filepath.Join(src, "main.js"))
+	os.Symlink(filepath.Join(src, "lib"), filepath.Join(src, "alias"))
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	if target, _ := os.Readlink(filepath.Join(dst, "main.js")); target != filepath.Join("lib", "index.js") {
+		t.Fatalf("Expected relative link to be kept, got %q", target)
+	}
+	if target, _ := os.Readlink(filepath.Join(dst, "alias")); target != "lib" {
+		t.Fatalf("Expected absolute link to be rewritten relative, got %q", target)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dst, "alias", "index.js")); string(content) != "lib" {
+		t.Fatal("Expected rewritten link to resolve inside the copy")
+	}
+
+	outside := t.TempDir()
+	os.Symlink(outside, filepath.Join(src, "escape"))
+	err := copyTree(src, filepath.Join(t.TempDir(), "dst"))
+	if err == nil || !strings.Contains(err.Error(), "outside the module") {
+		t.Fatalf("Expected escaping symlink to be refused, got %v", err)
+	}
+}
+
+func TestCopyTreePreservesModTimes(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("a"), 0755)
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chtimes(filepath.Join(src, "sub", "a.txt"), old, old)
+	os.Chtimes(filepath.Join(src, "sub"), old, old)
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	for _, p := range []string{"sub", "sub/a.txt"} {
+		info, err := os.Stat(filepath.Join(dst, p))
+		if err != nil {
+			t.Fatalf("Expected %s to be copied", p)
+		}
+		if !info.ModTime().Equal(old) {
+			t.Fatalf("Expected %s modtime %v, got %v", p, old, info.ModTime())
+		}
+	}
+	if info, _ := os.Stat(filepath.Join(dst, "sub", "a.txt")); info.Mode().Perm() != 0755 {
+		t.Fatalf("Expected mode 0755, got %v", info.Mode().Perm())
+	}
+}
+
+func TestCopyTreeDoesNotShareFiles(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	dst := filepath.Join(t.TempDir(), "dst")
+	os.MkdirAll(dst, 0755)
+
+	// An earlier install may have hardlinked the file into the project
+	if err := os.Link(filepath.Join(src, "a.txt"), filepath.Join(dst, "a.txt")); err != nil {
+		t.Skip("filesystem does not support hardlinks between temp directories")
+	}
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	srcInfo, _ := os.Stat(filepath.Join(src, "a.txt"))
+	dstInfo, _ := os.Stat(filepath.Join(dst, "a.txt"))
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Fatal("Expected the copy not to share the source file")
+	}
+
+	os.WriteFile(filepath.Join(dst, "a.txt"), []byte("edited"), 0644)
+	if content, _ := os.ReadFile(filepath.Join(src, "a.txt")); string(content) != "a" {
+		t.Fatal("Expected the source to be untouched")
+	}
+}
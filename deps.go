@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// moduleRequirement is one module's requirement on another.
+type moduleRequirement struct {
+	From  string
+	Range string
+}
+
+// resolvedModule is a module version picked by the dependency resolver.
+type resolvedModule struct {
+	Name    string
+	Version string
+	// Dir is a local copy of the module to install from.
+	Dir      string
+	Registry string
+	// Installed modules are already in the project and are left alone.
+	Installed bool
+}
+
+// dependencyResolver resolves the transitive dependencies of a module
+// against the modules already in the project and the configured registries.
+// Each module is resolved once, to the newest version satisfying the first
+// range seen; every later range must accept that version or resolution
+// fails with a conflict.
+type dependencyResolver struct {
+	sources  []string
+	lock     *Lockfile
+	resolved map[string]*resolvedModule
+	required map[string][]moduleRequirement
+	stack    []string
+	order    []*resolvedModule
+}
+
+// satisfies reports whether version is accepted by constraint. Modules that
+// declare no version only satisfy ranges that accept anything.
+func satisfies(version, constraint string) bool {
+	if version == "" {
+		c := strings.TrimSpace(constraint)
+		return c == "" || c == "*" || c == "latest"
+	}
+	return versionMatches(version, constraint)
+}
+
+// installedModuleNames returns the names a locked module can be depended on
+// by: its package name and, when it differs, the name in its manifest.
+func installedModuleNames(entry LockEntry) []string {
+	names := []string{entry.Name}
+	if manifest, err := loadModuleManifest(entry.Path); err == nil && manifest != nil &&
+		manifest.Name != "" && manifest.Name != entry.Name {
+		names = append(names, manifest.Name)
+	}
+	return names
+}
+
+// findInstalled returns the lock entry of the installed module known as name.
+func findInstalled(lock *Lockfile, name string) *LockEntry {
+	for i := range lock.Modules {
+		for _, n := range installedModuleNames(lock.Modules[i]) {
+			if n == name {
+				return &lock.Modules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// dependents returns the installed modules that depend on entry.
+func dependents(lock *Lockfile, entry LockEntry) []string {
+	names := installedModuleNames(entry)
+	var result []string
+	for _, other := range lock.Modules {
+		if other.Name == entry.Name {
+			continue
+		}
+		for _, name := range names {
+			if _, ok := other.Dependencies[name]; ok {
+				result = append(result, other.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// resolveDependencies works out which modules must be installed before the
+// module name, at version, found in dir. The result is in installation
+// order, dependencies first, and leaves out modules already installed.
+func resolveDependencies(dir, name, version string, sources []string) ([]*resolvedModule, error) {
+	lock, err := loadLockfile()
+	if err != nil {
+		return nil, err
+	}
+	r := &dependencyResolver{
+		sources:  sources,
+		lock:     lock,
+		resolved: make(map[string]*resolvedModule),
+		required: make(map[string][]moduleRequirement),
+	}
+
+	// Installed modules keep constraining what may be installed, except the
+	// module being replaced
+	for _, entry := range lock.Modules {
+		if entry.Name == name {
+			continue
+		}
+		for dep, constraint := range entry.Dependencies {
+			r.required[dep] = append(r.required[dep], moduleRequirement{entry.Name, constraint})
+		}
+	}
+
+	root := &resolvedModule{Name: name, Version: version, Dir: dir}
+	r.resolved[name] = root
+	// Dependents may refer to the module by its manifest name
+	if manifest, err := loadModuleManifest(dir); err == nil && manifest != nil &&
+		manifest.Name != "" && manifest.Name != name {
+		r.required[name] = append(r.required[name], r.required[manifest.Name]...)
+		r.resolved[manifest.Name] = root
+	}
+	if err := r.check(root); err != nil {
+		return nil, err
+	}
+	if err := r.visit(root); err != nil {
+		return nil, err
+	}
+	return r.order[:len(r.order)-1], nil
+}
+
+func (r *dependencyResolver) visit(m *resolvedModule) error {
+	r.stack = append(r.stack, m.Name)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	manifest, err := loadModuleManifest(m.Dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", m.Name, err)
+	}
+	var names []string
+	if manifest != nil {
+		for name := range manifest.Dependencies {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		constraint := manifest.Dependencies[name]
+		r.required[name] = append(r.required[name], moduleRequirement{m.Name, constraint})
+
+		for i, ancestor := range r.stack {
+			if ancestor == name {
+				cycle := append(append([]string{}, r.stack[i:]...), name)
+				return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+		if dep, ok := r.resolved[name]; ok {
+			if err := r.check(dep); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dep, err := r.pick(name, constraint)
+		if err != nil {
+			return err
+		}
+		r.resolved[name] = dep
+		if dep.Installed {
+			continue
+		}
+		if err := r.visit(dep); err != nil {
+			return err
+		}
+	}
+
+	r.order = append(r.order, m)
+	return nil
+}
+
+// pick chooses the version of name to use: the installed one if there is
+// one, otherwise the newest matching version in the registries.
+func (r *dependencyResolver) pick(name, constraint string) (*resolvedModule, error) {
+	if entry := findInstalled(r.lock, name); entry != nil {
+		dep := &resolvedModule{Name: name, Version: entry.Version, Installed: true}
+		return dep, r.check(dep)
+	}
+
+	from := r.required[name][len(r.required[name])-1].From
+	source, module, v, err := resolveRegistryModule(r.sources, name, constraint)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s (required by %s): %w", name, constraint, from, err)
+	}
+	if module == nil {
+		return nil, fmt.Errorf("module %s (required by %s) was not found in any registry", name, from)
+	}
+
+	dep := &resolvedModule{Name: name, Version: v.Version, Registry: source}
+	if err := r.check(dep); err != nil {
+		return nil, err
+	}
+	dep.Dir, err = fetchRegistryModule(source, module.Name, v)
+	if err != nil {
+		return nil, err
+	}
+	return dep, nil
+}
+
+// check makes sure every range required of m so far accepts its version.
+func (r *dependencyResolver) check(m *resolvedModule) error {
+	for _, req := range r.required[m.Name] {
+		if satisfies(m.Version, req.Range) {
+			continue
+		}
+		var reqs []string
+		for _, req := range r.required[m.Name] {
+			reqs = append(reqs, fmt.Sprintf("%s requires %s", req.From, req.Range))
+		}
+		version := m.Version
+		if version == "" {
+			version = "(unversioned)"
+		}
+		if m.Installed {
+			version += " (installed)"
+		}
+		return fmt.Errorf("version conflict for %s %s: %s", m.Name, version, strings.Join(reqs, ", "))
+	}
+	return nil
+}
+
+// installDependencies installs the modules the module in sourcePath depends
+// on, dependencies first, and fails without touching the project when they
+// can't be resolved.
+func installDependencies(sourcePath, moduleName string, moduleType ProjectType, opts installOptions) error {
+	version := opts.Version
+	if version == "" {
+		version = moduleVersion(sourcePath, moduleType)
+	}
+	sources := opts.Registries
+	if sources == nil {
+		sources = registrySources("")
+	}
+
+	deps, err := resolveDependencies(sourcePath, moduleName, version, sources)
+	if err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Installing %d dependencies of '%s':\n", len(deps), moduleName)
+	for _, dep := range deps {
+		fmt.Printf("  + %s %s (%s)\n", dep.Name, dep.Version, dep.Registry)
+	}
+	for _, dep := range deps {
+		err := installLocal(dep.Dir, installOptions{
+			Source:           "registry",
+			Origin:           dep.Registry,
+			Version:          dep.Version,
+			SkipDependencies: true,
+		})
+		if err != nil {
+			return fmt.Errorf("dependency %s: %w", dep.Name, err)
+		}
+	}
+	return nil
+}
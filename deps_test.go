@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestDependentModule creates a Node module whose manifest declares
+// dependencies.
+func writeTestDependentModule(t *testing.T, dir, name, version string, deps map[string]string) {
+	manifest, _ := json.Marshal(ModuleManifest{Name: name, Version: version, Dependencies: deps})
+	files := map[string]string{
+		"package.json":   `{"name": "` + name + `", "version": "` + version + `"}`,
+		"index.js":       "module.exports = {}\n",
+		manifestFileName: string(manifest),
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+}
+
+// newTestDependencyRegistry publishes modules, given as name@version keys,
+// into a new directory registry.
+func newTestDependencyRegistry(t *testing.T, modules map[string]map[string]string) string {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	registry := t.TempDir()
+	for ref, deps := range modules {
+		name, version := parseModuleRef(ref)
+		dir := t.TempDir()
+		writeTestDependentModule(t, dir, name, version, deps)
+		if _, _, err := publishModule(dir, registry, "", false); err != nil {
+			t.Fatalf("publishModule failed: %v", err)
+		}
+	}
+	return registry
+}
+
+func TestResolveDependencies(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"storage-sqlite@1.0.0": nil,
+		"storage-sqlite@1.4.0": nil,
+		"storage-sqlite@2.0.0": nil,
+		"reports@1.2.0":        {"storage-sqlite": "^1.2"},
+	})
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeTestDependentModule(t, dir, "dashboard", "1.0.0", map[string]string{"reports": "^1", "storage-sqlite": ">=1.0.0"})
+
+	deps, err := resolveDependencies(dir, "dashboard", "1.0.0", []string{registry})
+	if err != nil {
+		t.Fatalf("resolveDependencies failed: %v", err)
+	}
+	var got []string
+	for _, dep := range deps {
+		got = append(got, dep.Name+"@"+dep.Version)
+	}
+	if strings.Join(got, " ") != "storage-sqlite@1.4.0 reports@1.2.0" {
+		t.Fatalf("Unexpected resolution order: %v", got)
+	}
+}
+
+func TestResolveDependenciesConflict(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"storage-sqlite@1.4.0": nil,
+		"storage-sqlite@2.0.0": nil,
+		"reports@1.0.0":        {"storage-sqlite": "^1.0.0"},
+		"charts@1.0.0":         {"storage-sqlite": "^2.0.0"},
+	})
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeTestDependentModule(t, dir, "dashboard", "1.0.0", map[string]string{"reports": "*", "charts": "*"})
+
+	_, err := resolveDependencies(dir, "dashboard", "1.0.0", []string{registry})
+	if err == nil || !strings.Contains(err.Error(), "version conflict for storage-sqlite") {
+		t.Fatalf("Expected a version conflict, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "charts requires ^2.0.0") || !strings.Contains(err.Error(), "reports requires ^1.0.0") {
+		t.Fatalf("Expected the conflict to name both requirements, got %v", err)
+	}
+}
+
+func TestResolveDependenciesCycle(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"cycle-a@1.0.0": {"cycle-b": "^1"},
+		"cycle-b@1.0.0": {"dashboard": "^1"},
+	})
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeTestDependentModule(t, dir, "dashboard", "1.0.0", map[string]string{"cycle-a": "^1"})
+
+	_, err := resolveDependencies(dir, "dashboard", "1.0.0", []string{registry})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle: dashboard -> cycle-a -> cycle-b -> dashboard") {
+		t.Fatalf("Expected a dependency cycle, got %v", err)
+	}
+}
+
+func TestInstallLocalInstallsDependencies(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"storage-sqlite@1.4.0": nil,
+		"reports@1.2.0":        {"storage-sqlite": "^1.2"},
+	})
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeTestDependentModule(t, dir, "dashboard", "1.0.0", map[string]string{"reports": "^1"})
+	installLocalModule(dir, installOptions{Registries: []string{registry}})
+
+	lock, err := loadLockfile()
+	if err != nil {
+		t.Fatalf("loadLockfile failed: %v", err)
+	}
+	for _, name := range []string{"dashboard", "reports", "storage-sqlite"} {
+		if lock.find(name) == nil {
+			t.Fatalf("Expected %s to be installed", name)
+		}
+	}
+	if entry := lock.find("reports"); entry.Source != "registry" || entry.Dependencies["storage-sqlite"] != "^1.2" {
+		t.Fatalf("Unexpected lock entry: %+v", entry)
+	}
+
+	if got := dependents(lock, *lock.find("storage-sqlite")); strings.Join(got, ",") != "reports" {
+		t.Fatalf("Expected reports to depend on storage-sqlite, got %v", got)
+	}
+	if got := dependents(lock, *lock.find("dashboard")); len(got) != 0 {
+		t.Fatalf("Expected nothing to depend on dashboard, got %v", got)
+	}
+
+	// A new version of a module must still satisfy what depends on it
+	newer := t.TempDir()
+	writeTestDependentModule(t, newer, "storage-sqlite", "2.0.0", nil)
+	_, err = resolveDependencies(newer, "storage-sqlite", "2.0.0", []string{registry})
+	if err == nil || !strings.Contains(err.Error(), "reports requires ^1.2") {
+		t.Fatalf("Expected installing an incompatible version to conflict, got %v", err)
+	}
+}
+
+func TestInstallLocalFailsOnDependencyError(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"reports@1.2.0": nil,
+	})
+	priv := setupSigning(t, policyEnforce)
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	// The dependency is unsigned, so the enforced policy refuses it
+	dir := t.TempDir()
+	writeTestDependentModule(t, dir, "dashboard", "1.0.0", map[string]string{"reports": "^1"})
+	if _, err := signModule(dir, priv); err != nil {
+		t.Fatalf("signModule failed: %v", err)
+	}
+	err := installLocal(dir, installOptions{Registries: []string{registry}})
+	if err == nil || !strings.Contains(err.Error(), "dependency reports") {
+		t.Fatalf("Expected the dependency to fail, got %v", err)
+	}
+	if lock, _ := loadLockfile(); lock.find("dashboard") != nil {
+		t.Fatal("Expected dashboard not to be installed")
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"govite/generator"
+
+	"github.com/spf13/cobra"
+)
+
+const bindingsFile = "frontend/src/bindings.ts"
+
+// shellGoFiles reads the Go files of the desktop shell in the project root.
+func shellGoFiles(dir string) (map[string]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(path)] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s; run this in a go-vite project", dir)
+	}
+	return files, nil
+}
+
+func runGenBindings(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	files, err := shellGoFiles(".")
+	if err != nil {
+		return err
+	}
+	ts, err := generator.Bindings(files)
+	if err != nil {
+		return err
+	}
+	if existing, err := os.ReadFile(output); err == nil && string(existing) == ts {
+		fmt.Printf("%s is up to date\n", output)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, []byte(ts), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %s\n", output)
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunGenBindings(t *testing.T) {
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	generated, err := os.ReadFile(bindingsFile)
+	if err != nil {
+		t.Fatalf("Expected init to write %s: %v", bindingsFile, err)
+	}
+
+	os.WriteFile("files.go", []byte(`package main
+
+import "os"
+
+type FileInfo struct {
+	Name string `+"`json:\"name\"`"+`
+	Size int64  `+"`json:\"size\"`"+`
+}
+
+func (s *Service) ListFiles(dir string) ([]FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []FileInfo
+	for _, e := range entries {
+		files = append(files, FileInfo{Name: e.Name()})
+	}
+	return files, nil
+}
+`), 0644)
+	os.WriteFile("files_test.go", []byte("package main\n\nfunc (s *Service) TestOnly() {}\n"), 0644)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", bindingsFile, "")
+	if err := runGenBindings(cmd, nil); err != nil {
+		t.Fatalf("runGenBindings failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(bindingsFile)
+	ts := string(content)
+	if !strings.HasPrefix(ts, string(generated[:strings.Index(string(generated), "\n")])) {
+		t.Fatalf("Expected the generated header, got:\n%s", ts)
+	}
+	for _, want := range []string{
+		"export interface FileInfo {\n  name: string;\n  size: number;\n}",
+		"export function ListFiles(dir: string): Promise<FileInfo[]>",
+		"export function Greet(name: string): Promise<string>",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Fatalf("Expected %q in %s:\n%s", want, bindingsFile, ts)
+		}
+	}
+	if strings.Contains(ts, "TestOnly") {
+		t.Fatal("Expected test files to be ignored")
+	}
+}
+
+func TestRunGenBindingsOutsideProject(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	defer os.Chdir(oldWd)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", bindingsFile, "")
+	if err := runGenBindings(cmd, nil); err == nil || !strings.Contains(err.Error(), "no Go files") {
+		t.Fatalf("Expected an error outside a project, got %v", err)
+	}
+}
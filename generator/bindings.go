@@ -0,0 +1,393 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BindingService is the struct whose exported methods the desktop shell binds
+// into the webview.
+const BindingService = "Service"
+
+// BindingPrefix is prepended to a method name to form its webview binding,
+// so Service.Greet is window.go_Greet.
+const BindingPrefix = "go_"
+
+// Bindings renders frontend/src/bindings.ts for the Go files of the desktop
+// shell, keyed by file name. Every exported method of Service becomes a typed
+// async function, and the structs it uses become interfaces. A leading
+// context.Context parameter is supplied by the shell and left out. Methods
+// may return nothing, a value, an error, or a value and an error.
+func Bindings(files map[string]string) (string, error) {
+	b := &bindingsBuilder{
+		fset:    token.NewFileSet(),
+		types:   make(map[string]*ast.TypeSpec),
+		emitted: make(map[string]bool),
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var methods []*ast.FuncDecl
+	for _, name := range names {
+		file, err := parser.ParseFile(b.fset, name, files[name], 0)
+		if err != nil {
+			return "", err
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						b.types[spec.Name.Name] = spec
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil && decl.Name.IsExported() && receiverName(decl.Recv) == BindingService {
+					methods = append(methods, decl)
+				}
+			}
+		}
+	}
+
+	if _, ok := b.types[BindingService]; !ok {
+		return "", fmt.Errorf("no %s type to bind", BindingService)
+	}
+
+	var funcs strings.Builder
+	for _, method := range methods {
+		fn, err := b.function(method)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s.%s: %w", b.fset.Position(method.Pos()), BindingService, method.Name.Name, err)
+		}
+		funcs.WriteString(fn)
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by go-vite gen bindings. DO NOT EDIT.\n\n")
+	for _, decl := range b.decls {
+		out.WriteString(decl)
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(&out, bindingsRuntime, BindingPrefix)
+	out.WriteString(funcs.String())
+	return out.String(), nil
+}
+
+const bindingsRuntime = `/** BindingError is thrown when a native call fails or is unavailable. */
+export class BindingError extends Error {
+  constructor(
+    readonly binding: string,
+    message: string,
+  ) {
+    super(message);
+    this.name = 'BindingError';
+  }
+}
+
+type NativeFunction = (...args: unknown[]) => Promise<unknown>;
+
+/** isNative reports whether the page runs inside the desktop shell. */
+export function isNative(): boolean {
+  return '__govite' in window;
+}
+
+/**
+ * appToken returns this launch's app token, for requests that cannot carry
+ * the X-Govite-Token header, such as EventSource and WebSocket URLs. It is
+ * empty outside the desktop app.
+ */
+export function appToken(): string {
+  return (window as unknown as { __govite?: { token?: string } }).__govite?.token ?? '';
+}
+
+async function call<T>(name: string, ...args: unknown[]): Promise<T> {
+  const fn = (window as unknown as Record<string, NativeFunction | undefined>)['%[1]s' + name];
+  if (typeof fn !== 'function') {
+    throw new BindingError(name, ` + "`native binding ${name} is not available outside the desktop app`" + `);
+  }
+  try {
+    return (await fn(...args)) as T;
+  } catch (err) {
+    throw new BindingError(name, err instanceof Error ? err.message : String(err));
+  }
+}
+`
+
+type bindingsBuilder struct {
+	fset    *token.FileSet
+	types   map[string]*ast.TypeSpec
+	emitted map[string]bool
+	decls   []string
+}
+
+func receiverName(recv *ast.FieldList) string {
+	if len(recv.List) != 1 {
+		return ""
+	}
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func (b *bindingsBuilder) function(decl *ast.FuncDecl) (string, error) {
+	var params, args []string
+	fields := decl.Type.Params.List
+	if len(fields) > 0 && len(fields[0].Names) <= 1 && isContextType(fields[0].Type) {
+		// The shell passes the caller's context itself
+		fields = fields[1:]
+	}
+	for i, field := range fields {
+		if _, ok := field.Type.(*ast.Ellipsis); ok {
+			return "", fmt.Errorf("variadic parameters are not supported")
+		}
+		typ, err := b.tsType(field.Type)
+		if err != nil {
+			return "", err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", i))}
+		}
+		for _, name := range names {
+			param := tsIdentifier(name.Name)
+			params = append(params, param+": "+typ)
+			args = append(args, param)
+		}
+	}
+
+	var results []ast.Expr
+	if decl.Type.Results != nil {
+		for _, field := range decl.Type.Results.List {
+			for range max(1, len(field.Names)) {
+				results = append(results, field.Type)
+			}
+		}
+	}
+	if n := len(results); n > 0 && isErrorType(results[n-1]) {
+		results = results[:n-1]
+	}
+	result := "void"
+	switch len(results) {
+	case 0:
+	case 1:
+		typ, err := b.tsType(results[0])
+		if err != nil {
+			return "", err
+		}
+		result = typ
+	default:
+		return "", fmt.Errorf("must return at most a value and an error")
+	}
+
+	callArgs := strings.Join(append([]string{"'" + decl.Name.Name + "'"}, args...), ", ")
+	return fmt.Sprintf("\nexport function %s(%s): Promise<%s> {\n  return call<%s>(%s);\n}\n",
+		decl.Name.Name, strings.Join(params, ", "), result, result, callArgs), nil
+}
+
+func isContextType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
+
+func isErrorType(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// tsType maps a Go type to the TypeScript type of its JSON encoding.
+func (b *bindingsBuilder) tsType(expr ast.Expr) (string, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "string", nil
+		case "bool":
+			return "boolean", nil
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+			"uintptr", "float32", "float64", "byte", "rune":
+			return "number", nil
+		case "any":
+			return "unknown", nil
+		case "error":
+			return "", fmt.Errorf("error is only supported as the last result")
+		}
+		if spec, ok := b.types[t.Name]; ok {
+			if err := b.declare(spec); err != nil {
+				return "", err
+			}
+			return t.Name, nil
+		}
+		return "", fmt.Errorf("unknown type %s", t.Name)
+	case *ast.StarExpr:
+		typ, err := b.tsType(t.X)
+		if err != nil {
+			return "", err
+		}
+		return typ + " | null", nil
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") && t.Len == nil {
+			// encoding/json writes []byte as base64
+			return "string", nil
+		}
+		elem, err := b.tsType(t.Elt)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]", nil
+	case *ast.MapType:
+		key, err := b.tsType(t.Key)
+		if err != nil {
+			return "", err
+		}
+		if key != "string" && key != "number" {
+			return "", fmt.Errorf("map keys must be strings or numbers")
+		}
+		value, err := b.tsType(t.Value)
+		if err != nil {
+			return "", err
+		}
+		return "Record<" + key + ", " + value + ">", nil
+	case *ast.InterfaceType:
+		if t.Methods != nil && len(t.Methods.List) > 0 {
+			return "", fmt.Errorf("interfaces with methods are not supported")
+		}
+		return "unknown", nil
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			switch pkg.Name + "." + t.Sel.Name {
+			case "time.Time":
+				return "string", nil
+			case "time.Duration":
+				return "number", nil
+			case "json.RawMessage":
+				return "unknown", nil
+			}
+		}
+		return "", fmt.Errorf("unsupported type %s.%s", t.X, t.Sel.Name)
+	}
+	return "", fmt.Errorf("unsupported type %T", expr)
+}
+
+// declare emits the TypeScript declaration of a named type once.
+func (b *bindingsBuilder) declare(spec *ast.TypeSpec) error {
+	name := spec.Name.Name
+	if b.emitted[name] {
+		return nil
+	}
+	b.emitted[name] = true
+
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		typ, err := b.tsType(spec.Type)
+		if err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+		b.decls = append(b.decls, fmt.Sprintf("export type %s = %s;\n", name, typ))
+		return nil
+	}
+
+	var fields []string
+	if err := b.structFields(st, &fields); err != nil {
+		return fmt.Errorf("type %s: %w", name, err)
+	}
+	b.decls = append(b.decls, fmt.Sprintf("export interface %s {\n%s}\n", name, strings.Join(fields, "")))
+	return nil
+}
+
+// structFields appends the JSON fields of st, flattening embedded structs the
+// way encoding/json does.
+func (b *bindingsBuilder) structFields(st *ast.StructType, fields *[]string) error {
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		jsonName, opts, _ := strings.Cut(tag, ",")
+
+		if len(field.Names) == 0 {
+			ident, ok := field.Type.(*ast.Ident)
+			if ok && jsonName == "" {
+				if spec, ok := b.types[ident.Name]; ok {
+					if embedded, ok := spec.Type.(*ast.StructType); ok {
+						if err := b.structFields(embedded, fields); err != nil {
+							return err
+						}
+						continue
+					}
+				}
+			}
+			return fmt.Errorf("unsupported embedded field")
+		}
+
+		typ, err := b.tsType(field.Type)
+		if err != nil {
+			return err
+		}
+		optional := ""
+		if strings.Contains(","+opts+",", ",omitempty,") {
+			optional = "?"
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			key := jsonName
+			if key == "" {
+				key = name.Name
+			}
+			*fields = append(*fields, fmt.Sprintf("  %s%s: %s;\n", tsPropertyName(key), optional, typ))
+		}
+	}
+	return nil
+}
+
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true,
+}
+
+func tsIdentifier(name string) string {
+	if tsReserved[name] || name == "_" {
+		return name + "_"
+	}
+	return name
+}
+
+// tsPropertyName quotes JSON keys that are not plain identifiers.
+func tsPropertyName(key string) string {
+	for i, r := range key {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return "'" + strings.ReplaceAll(strings.ReplaceAll(key, `\`, `\\`), "'", `\'`) + "'"
+		}
+	}
+	return key
+}
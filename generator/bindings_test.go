@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBindings(t *testing.T) {
+	src := `package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Service struct{}
+
+type Status string
+
+type Base struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type Item struct {
+	Base
+	Name     string            ` + "`json:\"name\"`" + `
+	Tags     []string          ` + "`json:\"tags,omitempty\"`" + `
+	Meta     map[string]any    ` + "`json:\"meta\"`" + `
+	Parent   *Item             ` + "`json:\"parent\"`" + `
+	Status   Status            ` + "`json:\"status\"`" + `
+	Created  time.Time         ` + "`json:\"created-at\"`" + `
+	Raw      json.RawMessage   ` + "`json:\"raw\"`" + `
+	Data     []byte
+	internal string
+	Skipped  string ` + "`json:\"-\"`" + `
+}
+
+func (s *Service) List(filter string, limit int) ([]Item, error) { return nil, nil }
+func (s *Service) Save(item Item) error                          { return nil }
+func (s Service) Ping()                                           {}
+func (s *Service) private() string                               { return "" }
+func helper() string                                              { return "" }
+`
+	ts, err := Bindings(map[string]string{"bindings.go": src})
+	if err != nil {
+		t.Fatalf("Bindings failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"export type Status = string;",
+		"export interface Item {\n  id: string;\n  name: string;\n  tags?: string[];\n  meta: Record<string, unknown>;\n  parent: Item | null;\n  status: Status;\n  'created-at': string;\n  raw: unknown;\n  Data: string;\n}",
+		"export function List(filter: string, limit: number): Promise<Item[]> {\n  return call<Item[]>('List', filter, limit);\n}",
+		"export function Save(item: Item): Promise<void> {",
+		"export function Ping(): Promise<void> {",
+		"export class BindingError extends Error",
+		"export function appToken(): string {",
+		"['go_' + name]",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Fatalf("Expected %q in bindings:\n%s", want, ts)
+		}
+	}
+	for _, unwanted := range []string{"private", "helper", "internal", "Skipped", "interface Base"} {
+		if strings.Contains(ts, unwanted) {
+			t.Fatalf("Unexpected %q in bindings:\n%s", unwanted, ts)
+		}
+	}
+}
+
+func TestBindingsSkipContext(t *testing.T) {
+	src := `package main
+
+import "context"
+
+type Service struct{}
+
+func (s *Service) Read(ctx context.Context, path string) (string, error) { return "", nil }
+`
+	ts, err := Bindings(map[string]string{"bindings.go": src})
+	if err != nil {
+		t.Fatalf("Bindings failed: %v", err)
+	}
+	if !strings.Contains(ts, "export function Read(path: string): Promise<string> {\n  return call<string>('Read', path);") {
+		t.Fatalf("Expected the context parameter to be left out:\n%s", ts)
+	}
+}
+
+func TestBindingsErrors(t *testing.T) {
+	tests := map[string]string{
+		"func (s *Service) A() (int, string, error) { return 0, \"\", nil }": "must return at most a value and an error",
+		"func (s *Service) A(ch chan int) {}":                                "unsupported type",
+		"func (s *Service) A(args ...string) {}":                             "variadic parameters are not supported",
+		"func (s *Service) A(o Other) {}":                                    "unknown type Other",
+		"func (s *Service) A(m map[bool]string) {}":                          "map keys must be strings or numbers",
+	}
+	for method, want := range tests {
+		src := "package main\n\ntype Service struct{}\n\n" + method + "\n"
+		_, err := Bindings(map[string]string{"bindings.go": src})
+		if err == nil || !strings.Contains(err.Error(), want) || !strings.Contains(err.Error(), "bindings.go:5:1: Service.A") {
+			t.Errorf("Bindings(%q) = %v, want error containing %q", method, err, want)
+		}
+	}
+
+	if _, err := Bindings(map[string]string{"main.go": "package main\n"}); err == nil || !strings.Contains(err.Error(), "no Service type") {
+		t.Fatalf("Expected an error without a Service type, got %v", err)
+	}
+}
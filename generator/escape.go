@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Each template splices config values through the encoder for the format it
+// is writing, so a name or description can never break out of its context.
+
+// jsonString returns s as a quoted JSON string.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// tsxString returns s as a JSX expression holding a JavaScript string
+// literal. JSON strings are valid JavaScript literals; HTML escaping keeps
+// "</script>" and friends inert wherever the bundle ends up.
+func tsxString(s string) string {
+	data, _ := json.Marshal(s)
+	return "{" + string(data) + "}"
+}
+
+// htmlText returns s escaped for HTML text content.
+func htmlText(s string) string {
+	return html.EscapeString(s)
+}
+
+// tomlString returns s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// singleLine replaces line breaks in s with spaces, for line-oriented
+// formats such as comments, .env files and Markdown headings.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s)
+}
+
+// makeValue returns s escaped for the right-hand side of a Makefile variable
+// assignment or a comment.
+func makeValue(s string) string {
+	return strings.NewReplacer("$", "$$", "#", `\#`).Replace(singleLine(s))
+}
+
+// makeShellArg returns s as a single shell word inside a Makefile recipe.
+func makeShellArg(s string) string {
+	quoted := "'" + strings.ReplaceAll(singleLine(s), "'", `'\''`) + "'"
+	return strings.ReplaceAll(quoted, "$", "$$")
+}
+
+// goModPath returns a module path for a go.mod module directive, quoting it
+// when it is not a plain token.
+func goModPath(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"'`\\{}()") || strings.Contains(s, "//") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGenerateEscapesConfigValues(t *testing.T) {
+	config := testConfig()
+	config.Name = `my "app" <b>`
+	config.Description = `Say "hi" & </p><script>alert(1)</script> for $(HOME) #1`
+
+	fsys := NewMemFS()
+	if err := Generate(context.Background(), config, fsys); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	read := func(name string) string {
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			t.Fatalf("Expected %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	var pkg struct{ Name, Description string }
+	if err := json.Unmarshal([]byte(read("frontend/package.json")), &pkg); err != nil {
+		t.Fatalf("package.json is invalid: %v", err)
+	}
+	if pkg.Name != config.Name || pkg.Description != config.Description {
+		t.Fatalf("Expected package.json to round-trip, got %+v", pkg)
+	}
+
+	if html := read("frontend/index.html"); !strings.Contains(html, "<title>my &#34;app&#34; &lt;b&gt;</title>") {
+		t.Fatalf("Expected an escaped title, got:\n%s", html)
+	}
+
+	app := read("frontend/src/App.tsx")
+	if strings.Contains(app, "<script>") || strings.Contains(app, "<b>") {
+		t.Fatalf("Expected no injected markup in App.tsx, got:\n%s", app)
+	}
+	if !strings.Contains(app, `{"Say \"hi\" \u0026 \u003c/p\u003e`) {
+		t.Fatalf("Expected the description as a string literal, got:\n%s", app)
+	}
+
+	if toml := read("netlify.toml"); !strings.Contains(toml, `VITE_APP_NAME = "my \"app\" <b>"`) {
+		t.Fatalf("Expected an escaped TOML string, got:\n%s", toml)
+	}
+
+	makefile := read("Makefile")
+	for _, expected := range []string{
+		`PROJECT_NAME := my "app" <b>`,
+		`-o $(DIST_DIR)/'my "app" <b>' .`,
+	} {
+		if !strings.Contains(makefile, expected) {
+			t.Fatalf("Expected %q in the Makefile, got:\n%s", expected, makefile)
+		}
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	cases := []struct{ got, expected string }{
+		{tomlString("a\"b\\c\nd\x01"), `"a\"b\\c\nd\u0001"`},
+		{makeValue("$(x) #y\nz"), `$$(x) \#y z`},
+		{makeShellArg("it's $HOME"), `'it'\''s $$HOME'`},
+		{goModPath("github.com/a/b"), "github.com/a/b"},
+		{goModPath("my app"), `"my app"`},
+		{jsonString("<a>"), `"<a>"`},
+	}
+	for _, c := range cases {
+		if c.got != c.expected {
+			t.Fatalf("Expected %s, got %s", c.expected, c.got)
+		}
+	}
+}
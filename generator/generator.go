@@ -0,0 +1,191 @@
+// Package generator creates Go-Vite projects: a webview desktop shell, a Gin
+// backend and a React + Vite frontend. It is the library behind
+// `go-vite init` and writes through an FS, so projects can be generated to
+// disk or into memory.
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Config describes the project to generate.
+type Config struct {
+	// Name is the project name, used for the binary and the app title.
+	Name string
+	// Module is the Go module path of the desktop app.
+	Module      string
+	Description string
+	Author      string
+	// Port is the Vite dev server port and BackendPort the Gin server port.
+	Port        int
+	BackendPort int
+	// Backend is how the desktop shell runs the backend, BackendSidecar
+	// when empty.
+	Backend string
+	// Transport is how the webview reaches the shell, TransportHTTP when
+	// empty.
+	Transport string
+}
+
+// Backend modes.
+const (
+	// BackendSidecar embeds the backend binary, runs it as a child process
+	// and proxies /api to it.
+	BackendSidecar = "sidecar"
+	// BackendInProcess compiles the backend's routes into the shell and
+	// serves /api without a second process.
+	BackendInProcess = "inprocess"
+)
+
+// Transports.
+const (
+	// TransportHTTP serves the frontend and /api on a local port.
+	TransportHTTP = "http"
+	// TransportBridge tunnels the frontend's requests through a webview
+	// binding, so the shell opens no port.
+	TransportBridge = "bridge"
+)
+
+// Dirs returns the directories of a generated project, including empty ones
+// that are part of the layout.
+func Dirs() []string {
+	return []string{
+		"backend/cmd/server",
+		"backend/config",
+		"backend/server",
+		"backend/internal/api/handlers",
+		"backend/internal/api/middleware",
+		"backend/internal/models",
+		"backend/internal/modules",
+		"backend/internal/storage",
+		"backend/internal/storage/migrations",
+		"backend/internal/utils",
+		"backend/tests",
+		"frontend/src/components",
+		"frontend/src/pages",
+		"frontend/src/hooks",
+		"frontend/src/services",
+		"frontend/src/utils",
+		"frontend/public",
+		"frontend/modules",
+		"netlify/functions",
+		"dist",
+		"bin",
+	}
+}
+
+// Files returns the contents of every generated file, keyed by its
+// slash-separated path relative to the project root.
+func Files(config Config) map[string]string {
+	files := map[string]string{
+		// Root files
+		"go.mod":          generateGoMod(config),
+		"main.go":         generateMainGo(),
+		"bindings.go":     generateBindingsGo(),
+		"capabilities.go": generateCapabilitiesGo(),
+		"bridge.go":       generateBridgeGo(),
+		"guard.go":        generateGuardGo(),
+		"startup.go":      generateStartupGo(),
+		"govite.json":     generateManifest(config),
+		"Makefile":        generateMakefile(config),
+		"README.md":       generateReadme(config),
+		".gitignore":      generateGitignore(),
+		".gitattributes":  generateGitattributes(),
+		".env.example":    generateEnvExample(config),
+		"netlify.toml":    generateNetlifyToml(config),
+
+		// Backend files
+		"backend/go.mod":                                generateBackendGoMod(),
+		"backend/cmd/server/main.go":                    generateBackendMain(),
+		"backend/config/config.go":                      generateConfig(),
+		"backend/server/server.go":                      generateBackendServer(),
+		"backend/internal/api/routes.go":                generateRoutes(),
+		"backend/internal/api/handlers/handlers.go":     generateHandlers(),
+		"backend/internal/api/middleware/logger.go":     generateLoggerMiddleware(),
+		"backend/internal/models/pipeline.go":           generatePipelineModel(),
+		"backend/internal/models/project.go":            generateProjectModel(),
+		"backend/internal/models/user.go":               generateUserModel(),
+		"backend/internal/modules/modules.go":           generateModulesManager(),
+		"backend/internal/modules/builtin.go":           generateBuiltinModules(),
+		"backend/internal/modules/node.go":              NodeModuleRunner(),
+		"backend/internal/storage/database.go":          generateDatabase(),
+		"backend/internal/storage/migrations.go":        generateMigrations(),
+		"backend/internal/storage/migrations/README.md": generateMigrationsReadme(),
+		"backend/internal/utils/logger.go":              generateLogger(),
+
+		// Frontend files
+		"frontend/package.json":       generatePackageJson(config),
+		"frontend/vite.config.js":     generateViteConfig(config),
+		"frontend/tailwind.config.js": generateTailwindConfig(),
+		"frontend/postcss.config.js":  generatePostcssConfig(),
+		"frontend/index.html":         generateIndexHtml(config),
+		"frontend/src/main.tsx":       generateMainTsx(),
+		"frontend/src/App.tsx":        generateAppTsx(config),
+		"frontend/src/bindings.ts":    generateBindingsTs(),
+		"frontend/src/index.css":      generateIndexCss(),
+		"frontend/.eslintrc.cjs":      generateEslintrc(),
+		"frontend/.prettierrc":        generatePrettierrc(),
+
+		// Netlify files
+		"netlify/functions/api.js": generateNetlifyApiFunction(),
+	}
+
+	// Only the sidecar needs the reverse proxy and the supervisor
+	if config.Backend == BackendInProcess {
+		files["backend.go"] = generateBackendInProcessGo()
+	} else {
+		files["backend.go"] = generateBackendSidecarGo()
+		files["proxy.go"] = generateProxyGo()
+		files["supervisor.go"] = generateSupervisorGo()
+	}
+	return files
+}
+
+// Generate writes a new project described by config to fsys. The rendered
+// files are checked with Validate first, so nothing is written if any of them
+// is invalid. It stops with ctx's error if ctx is cancelled before every file
+// is written.
+func Generate(ctx context.Context, config Config, fsys FS) error {
+	switch config.Backend {
+	case "", BackendSidecar, BackendInProcess:
+	default:
+		return fmt.Errorf("unknown backend mode %q (expected %s or %s)", config.Backend, BackendSidecar, BackendInProcess)
+	}
+	switch config.Transport {
+	case "", TransportHTTP, TransportBridge:
+	default:
+		return fmt.Errorf("unknown transport %q (expected %s or %s)", config.Transport, TransportHTTP, TransportBridge)
+	}
+
+	files := Files(config)
+	if err := Validate(files); err != nil {
+		return err
+	}
+
+	for _, dir := range Dirs() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fsys.WriteFile(path, []byte(files[path]), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testConfig() Config {
+	return Config{
+		Name:        "test-app",
+		Module:      "github.com/test/test-app",
+		Description: "Test application",
+		Port:        5173,
+		BackendPort: 8080,
+	}
+}
+
+func TestGenerateMemFS(t *testing.T) {
+	fsys := NewMemFS()
+	if err := Generate(context.Background(), testConfig(), fsys); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	files := Files(testConfig())
+	if got := fsys.Files(); len(got) != len(files) {
+		t.Fatalf("Expected %d files, got %d", len(files), len(got))
+	}
+	for path, content := range files {
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected %s to be written: %v", path, err)
+		}
+		if string(data) != content {
+			t.Fatalf("Unexpected content for %s", path)
+		}
+	}
+	for _, dir := range Dirs() {
+		if !fsys.IsDir(dir) {
+			t.Fatalf("Expected directory %s", dir)
+		}
+	}
+
+	goMod, _ := fsys.ReadFile("go.mod")
+	if !strings.Contains(string(goMod), "module github.com/test/test-app") {
+		t.Fatalf("Expected module path in go.mod, got: %s", goMod)
+	}
+}
+
+func TestGenerateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fsys := NewMemFS()
+	if err := Generate(ctx, testConfig(), fsys); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(fsys.Files()) != 0 {
+		t.Fatal("Expected nothing to be written")
+	}
+}
+
+func TestGenerateDirFS(t *testing.T) {
+	root := t.TempDir()
+	if err := Generate(context.Background(), testConfig(), DirFS(root)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for path := range Files(testConfig()) {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(path))); err != nil {
+			t.Fatalf("Expected %s on disk", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "frontend", "modules")); err != nil {
+		t.Fatal("Expected empty layout directories on disk")
+	}
+}
+
+// TestGeneratedBackendBuilds compiles the generated backend. Validate only
+// parses Go files, which misses type errors such as unused variables.
+func TestGeneratedBackendBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated backend")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+
+	root := t.TempDir()
+	if err := Generate(context.Background(), testConfig(), DirFS(root)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	env := append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = filepath.Join(root, "backend")
+		cmd.Env = env
+		return cmd.CombinedOutput()
+	}
+	if output, err := run("mod", "download"); err != nil {
+		t.Skipf("Backend dependencies are unavailable: %v\n%s", err, output)
+	}
+	if output, err := run("build", "-o", os.DevNull, "./..."); err != nil {
+		t.Fatalf("Generated backend does not build: %v\n%s", err, output)
+	}
+}
+
+func TestFSRejectsInvalidPaths(t *testing.T) {
+	for _, fsys := range []FS{DirFS(t.TempDir()), NewMemFS()} {
+		for _, name := range []string{"../escape", "/abs", "a/../b"} {
+			if err := fsys.WriteFile(name, nil, 0644); err == nil {
+				t.Fatalf("%T: expected %q to be rejected", fsys, name)
+			}
+			if err := fsys.MkdirAll(name, 0755); err == nil {
+				t.Fatalf("%T: expected %q to be rejected", fsys, name)
+			}
+		}
+	}
+}
+
+func TestGenerateBackendModes(t *testing.T) {
+	sidecar := Files(testConfig())
+	if !strings.Contains(sidecar["backend.go"], "//go:embed bin/backend") {
+		t.Fatal("Expected the sidecar backend to embed the backend binary")
+	}
+	if !strings.Contains(sidecar["Makefile"], "binary: frontend backend\n") {
+		t.Fatal("Expected the sidecar binary to depend on the backend build")
+	}
+	if !strings.Contains(sidecar["backend.go"], `"HOST=127.0.0.1"`) || !strings.Contains(sidecar["backend.go"], "freePort()") {
+		t.Fatal("Expected the sidecar backend to get a free loopback port")
+	}
+	if strings.Contains(sidecar["backend.go"], "time.Sleep") || !strings.Contains(sidecar["backend.go"], "app.backend.supervisor.Start()") {
+		t.Fatal("Expected the sidecar backend to be supervised instead of sleeping")
+	}
+	if !strings.Contains(sidecar["main.go"], `"/__govite/status"`) {
+		t.Fatal("Expected the supervisor status endpoint")
+	}
+	if !strings.Contains(sidecar["backend.go"], "newBackendProxy(") || strings.Contains(sidecar["main.go"], "WriteTimeout") {
+		t.Fatal("Expected /api to go through the reverse proxy without a write timeout")
+	}
+	if !strings.Contains(sidecar["backend.go"], `"GOVITE_SOCKET="+app.backend.socket`) || !strings.Contains(sidecar["backend/cmd/server/main.go"], `net.Listen("unix", socket)`) {
+		t.Fatal("Expected the sidecar backend to listen on a Unix socket")
+	}
+	if !strings.Contains(sidecar["backend/cmd/server/main.go"], `fmt.Println("GOVITE_READY")`) {
+		t.Fatal("Expected the backend to announce readiness")
+	}
+	if !strings.Contains(sidecar["main.go"], "w.SetHtml(startupErrorPage(startErr))") {
+		t.Fatal("Expected a failed start to show an error page")
+	}
+
+	config := testConfig()
+	config.Backend = BackendInProcess
+	fsys := NewMemFS()
+	if err := Generate(context.Background(), config, fsys); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	backend, _ := fsys.ReadFile("backend.go")
+	if !strings.Contains(string(backend), "server.Mount(app.backend.router)") {
+		t.Fatalf("Expected the in-process backend to mount the backend routes, got:\n%s", backend)
+	}
+	for _, want := range []string{"app.manifest.routeContext(c.Request)", "withoutParam(r.URL.RawQuery, tokenParam)"} {
+		if !strings.Contains(string(backend), want) {
+			t.Fatalf("Expected the in-process backend to use %s, got:\n%s", want, backend)
+		}
+	}
+	for _, path := range []string{"proxy.go", "supervisor.go"} {
+		if _, err := fsys.ReadFile(path); err == nil {
+			t.Fatalf("Expected no %s in-process", path)
+		}
+		if _, ok := sidecar[path]; !ok {
+			t.Fatalf("Expected %s for the sidecar", path)
+		}
+	}
+	for _, path := range fsys.Files() {
+		content, _ := fsys.ReadFile(path)
+		if strings.Contains(string(content), "go:embed bin/backend") {
+			t.Fatalf("Expected no embedded backend binary in-process, found in %s", path)
+		}
+	}
+	makefile, _ := fsys.ReadFile("Makefile")
+	if !strings.Contains(string(makefile), "binary: frontend\n") {
+		t.Fatal("Expected the in-process binary not to depend on the backend build")
+	}
+
+	config.Backend = "thread"
+	if err := Generate(context.Background(), config, NewMemFS()); err == nil || !strings.Contains(err.Error(), "unknown backend mode") {
+		t.Fatalf("Expected an unknown backend mode to fail, got %v", err)
+	}
+}
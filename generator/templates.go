@@ -0,0 +1,3174 @@
+package generator
+
+import (
+	"fmt"
+)
+
+func generateGoMod(config Config) string {
+	return fmt.Sprintf(`module %s
+
+go 1.24.0
+
+require (
+	backend v0.0.0-00010101000000-000000000000
+	github.com/gin-gonic/gin v1.9.1
+	github.com/joho/godotenv v1.5.1
+	github.com/webview/webview_go v0.0.0-20240831120633-6173450d4dd6
+)
+
+replace backend => ./backend
+`, goModPath(config.Module))
+}
+
+func generateMainGo() string {
+	return `package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend/config"
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"github.com/webview/webview_go"
+)
+
+//go:embed all:frontend/dist
+var distFS embed.FS
+
+//go:embed govite.json
+var manifestJSON []byte
+
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
+
+type EmbeddedFS struct {
+	files fs.FS
+}
+
+func NewEmbeddedFS() (*EmbeddedFS, error) {
+	files, err := fs.Sub(distFS, "frontend/dist")
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddedFS{files: files}, nil
+}
+
+func (efs *EmbeddedFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "" {
+		path = "index.html"
+	}
+
+	file, err := efs.files.Open(path)
+	if err != nil {
+		if !strings.Contains(path, ".") {
+			htmlPath := path + ".html"
+			if file, err = efs.files.Open(htmlPath); err != nil {
+				if file, err = efs.files.Open("index.html"); err != nil {
+					http.NotFound(w, r)
+					return
+				}
+			}
+		} else {
+			http.NotFound(w, r)
+			return
+		}
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case ".css":
+		w.Header().Set("Content-Type", "text/css")
+	case ".js":
+		w.Header().Set("Content-Type", "application/javascript")
+	case ".json":
+		w.Header().Set("Content-Type", "application/json")
+	case ".png":
+		w.Header().Set("Content-Type", "image/png")
+	case ".svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+	}
+
+	http.ServeContent(w, r, stat.Name(), stat.ModTime(), file.(io.ReadSeeker))
+}
+
+type App struct {
+	config   *config.Config
+	router   *gin.Engine
+	server   *http.Server
+	backend  backend
+	manifest *Manifest
+	policy   *Policy
+	// url is where the frontend is served, known once Start is listening
+	url string
+	// token is this launch's secret, required on /api requests
+	token string
+	guard *requestGuard
+	// onDenied is called with a message when a capability is denied
+	onDenied func(message string)
+}
+
+func NewApp(cfg *config.Config) (*App, error) {
+	manifest, err := LoadManifest(manifestJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the app token: %w", err)
+	}
+
+	// Start sets the app origin and host once it has a port. Pages loaded
+	// with SetHtml have an opaque origin, and bridge requests go to the
+	// host "bridge".
+	appOrigin, appHost := "", ""
+	if manifest.Transport == transportBridge {
+		appOrigin, appHost = "null", "bridge"
+	}
+
+	app := &App{
+		config:   cfg,
+		router:   gin.New(),
+		manifest: manifest,
+		policy:   NewPolicy(manifest, appOrigin),
+		token:    token,
+		guard:    &requestGuard{token: token, host: appHost, origin: appOrigin},
+	}
+
+	if err := app.setupBackend(); err != nil {
+		return nil, fmt.Errorf("failed to set up backend: %w", err)
+	}
+
+	app.router.Use(gin.Logger())
+	app.router.Use(gin.Recovery())
+	app.router.Use(app.guardRequests())
+
+	if err := app.setupRoutes(); err != nil {
+		return nil, fmt.Errorf("failed to setup routes: %w", err)
+	}
+
+	return app, nil
+}
+
+func (app *App) setupRoutes() error {
+	embeddedFS, err := NewEmbeddedFS()
+	if err != nil {
+		return err
+	}
+
+	app.router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":     "healthy",
+			"version":    Version,
+			"build_time": BuildTime,
+			"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		})
+	})
+
+	// Supervisor state for the frontend and its devtools
+	app.router.GET("/__govite/status", func(c *gin.Context) {
+		if origin := requestOrigin(c.Request); origin == "" || origin != app.policy.AppOrigin {
+			app.deny(origin, "GET /__govite/status")
+			c.JSON(403, gin.H{"error": "Status is only available to the app"})
+			return
+		}
+		c.JSON(200, app.backendStatus())
+	})
+
+	api := app.router.Group("/api")
+	{
+		api.Any("/*path", func(c *gin.Context) {
+			origin := requestOrigin(c.Request)
+			if !app.policy.AllowRoute(origin, c.Request.Method, c.Request.URL.Path) {
+				app.deny(origin, c.Request.Method+" "+c.Request.URL.Path)
+				c.JSON(403, gin.H{"error": "Route not allowed by govite.json"})
+				return
+			}
+
+			app.serveBackend(c)
+		})
+	}
+
+	app.router.NoRoute(func(c *gin.Context) {
+		embeddedFS.ServeHTTP(c.Writer, c.Request)
+	})
+
+	return nil
+}
+
+// deny logs a call the capability policy refused.
+func (app *App) deny(origin, what string) {
+	message := fmt.Sprintf("Denied %s for origin %q: not granted in govite.json", what, origin)
+	log.Print(message)
+	if app.onDenied != nil {
+		app.onDenied(message)
+	}
+}
+
+func (app *App) Start() error {
+	if err := app.startBackend(); err != nil {
+		return err
+	}
+
+	if app.manifest.Transport == transportBridge {
+		log.Println("Using the bridge transport, not listening on any port")
+		return nil
+	}
+
+	// Listen on loopback only, on a port the kernel picks unless
+	// FRONTEND_PORT asks for a fixed one
+	addr := "127.0.0.1:0"
+	if _, ok := os.LookupEnv("FRONTEND_PORT"); ok {
+		addr = fmt.Sprintf("127.0.0.1:%d", app.config.FrontendPort)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	app.url = "http://" + listener.Addr().String()
+	app.policy.AppOrigin = app.url
+	app.guard.host, app.guard.origin = listener.Addr().String(), app.url
+
+	// No read or write timeout, so streams and WebSockets stay open. The
+	// proxy limits each backend request by its route's timeout instead.
+	app.server = &http.Server{
+		Handler:           app.router,
+		ReadHeaderTimeout: 15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Serving application at %s", app.url)
+		if err := app.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (app *App) Stop() error {
+	if app.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := app.server.Shutdown(ctx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+		}
+	}
+
+	app.stopBackend()
+
+	return nil
+}
+
+// guardRequests rejects requests for another host or from another origin,
+// and /api or /__govite requests without this launch's token. The app is
+// same-origin, so it sends no CORS headers.
+func (app *App) guardRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		needToken := strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/__govite/")
+		if status, err := app.guard.check(c.Request, needToken); err != nil {
+			log.Printf("Rejected %s %s (Host %q, Origin %q): %v", c.Request.Method, path, c.Request.Host, c.Request.Header.Get("Origin"), err)
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+func main() {
+	fmt.Printf("Application v%s (built %s, commit %s)\n", Version, BuildTime, GitCommit)
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if err := config.LoadConfig(); err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	cfg := config.GetConfig()
+
+	app, err := NewApp(&cfg)
+	if err != nil {
+		log.Fatalf("Failed to create application: %v", err)
+	}
+
+	// A failed start is reported in the window rather than as a silent exit
+	startErr := app.Start()
+	if startErr != nil {
+		log.Printf("Failed to start application: %v", startErr)
+	}
+
+	w := webview.New(true)
+	defer w.Destroy()
+	w.SetTitle("Application")
+	w.SetSize(1200, 800, webview.HintNone)
+	w.Init(tokenScript(app.token, app.guard.origin))
+	if err := bindService(w, app, &Service{app: app}); err != nil {
+		log.Fatalf("Failed to bind native functions: %v", err)
+	}
+	if Version == "dev" || os.Getenv("GOVITE_DEV") != "" {
+		// Surface denied calls in the devtools console while developing
+		app.onDenied = func(message string) {
+			script, _ := json.Marshal(message)
+			w.Dispatch(func() { w.Eval("console.warn(" + string(script) + ")") })
+		}
+	}
+
+	if startErr != nil {
+		w.SetHtml(startupErrorPage(startErr))
+	} else if app.manifest.Transport == transportBridge {
+		err := w.Bind("__govite_fetch", func(secret string, req bridgeRequest) (bridgeResponse, error) {
+			return bridgeCall(app.router, app.guard, secret, req)
+		})
+		if err != nil {
+			log.Fatalf("Failed to bind the bridge transport: %v", err)
+		}
+		w.Init(bridgeScript(app.guard.origin, app.token))
+		log.Println("Opening webview over the bridge transport")
+		w.SetHtml(bridgePage)
+	} else {
+		log.Printf("Opening webview at %s", app.url)
+		w.Navigate(app.url)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		log.Println("Received shutdown signal, terminating webview...")
+		w.Terminate()
+	}()
+
+	w.Run()
+
+	log.Println("Shutting down...")
+	if err := app.Stop(); err != nil {
+		log.Printf("Error during shutdown: %v", err)
+	}
+
+	log.Println("Application stopped")
+}
+`
+}
+
+func generateBackendSidecarGo() string {
+	return `package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed bin/backend
+var backendBinary []byte
+
+// backend runs the embedded backend binary as a supervised child process
+// and proxies /api requests to it.
+type backend struct {
+	path    string
+	tempDir string
+	// socket is the backend's Unix socket in socketDir, or empty when it
+	// listens on port
+	socket     string
+	socketDir  string
+	port       int
+	proxy      *backendProxy
+	supervisor *supervisor
+	logs       *rotatingLog
+}
+
+const (
+	// backendReadyTimeout is how long the backend has to become ready.
+	backendReadyTimeout = 30 * time.Second
+	// The backend's output is kept in logs/backend.log under the app data
+	// dir, rotated at 5MB with 3 old files kept.
+	backendLogSize  = 5 << 20
+	backendLogFiles = 3
+)
+
+// setupBackend extracts the backend binary to a temporary directory.
+func (app *App) setupBackend() error {
+	tempDir, err := os.MkdirTemp("", "app-*")
+	if err != nil {
+		return err
+	}
+	app.backend.tempDir = tempDir
+
+	app.backend.path = filepath.Join(tempDir, "backend")
+	file, err := os.Create(app.backend.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(backendBinary); err != nil {
+		return err
+	}
+
+	return os.Chmod(app.backend.path, 0755)
+}
+
+func (app *App) serveBackend(c *gin.Context) {
+	app.backend.proxy.ServeHTTP(c.Writer, c.Request)
+}
+
+// freePort asks the kernel for an unused loopback port.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func (app *App) startBackend() error {
+	env := append(os.Environ(), "GIN_MODE=release")
+	target := &url.URL{Scheme: "http", Host: "backend"}
+
+	// The backend listens on a Unix socket in a private directory, so no
+	// other local user can reach it. BACKEND_PORT asks for TCP instead, and
+	// TCP is the fallback where Unix sockets do not work.
+	if _, ok := os.LookupEnv("BACKEND_PORT"); !ok {
+		dir, socket, err := newSocketDir()
+		if err != nil {
+			log.Printf("Unix sockets are unavailable, using TCP: %v", err)
+		} else {
+			app.backend.socketDir, app.backend.socket = dir, socket
+		}
+	}
+	if app.backend.socket != "" {
+		env = append(env, "GOVITE_SOCKET="+app.backend.socket)
+		log.Printf("Starting backend service on %s...", app.backend.socket)
+	} else {
+		// A free port unless BACKEND_PORT asks for a fixed one
+		app.backend.port = app.config.BackendPort
+		if _, ok := os.LookupEnv("BACKEND_PORT"); !ok {
+			port, err := freePort()
+			if err != nil {
+				return fmt.Errorf("failed to find a free port: %w", err)
+			}
+			app.backend.port = port
+		}
+		env = append(env, "HOST=127.0.0.1", fmt.Sprintf("PORT=%d", app.backend.port))
+		target.Host = fmt.Sprintf("127.0.0.1:%d", app.backend.port)
+		log.Printf("Starting backend service on port %d...", app.backend.port)
+	}
+	// The backend only answers requests for target.Host with the token
+	env = append(env, "GOVITE_TOKEN="+app.token, "GOVITE_HOST="+target.Host)
+	transport := newBackendTransport(app.backend.socket)
+	app.backend.proxy = newBackendProxy(target, transport, app.token, app.manifest)
+
+	var output io.Writer = os.Stdout
+	logPath := filepath.Join(app.manifest.AppDataDir(), "logs", "backend.log")
+	logs, err := openRotatingLog(logPath, backendLogSize, backendLogFiles)
+	if err != nil {
+		log.Printf("Backend output is not logged to a file: %v", err)
+	} else {
+		app.backend.logs = logs
+		output = io.MultiWriter(os.Stdout, logs)
+		log.Printf("Backend output is logged to %s", logPath)
+	}
+
+	app.backend.supervisor = &supervisor{
+		command: func() *exec.Cmd {
+			cmd := exec.Command(app.backend.path)
+			cmd.Env = env
+			return cmd
+		},
+		healthURL:    target.String() + "/health",
+		transport:    transport,
+		readyTimeout: backendReadyTimeout,
+		output:       output,
+		minBackoff:   500 * time.Millisecond,
+		maxBackoff:   30 * time.Second,
+		maxRestarts:  5,
+		crashWindow:  time.Minute,
+	}
+	if err := app.backend.supervisor.Start(); err != nil {
+		return err
+	}
+	log.Printf("Backend is ready (PID: %d)", app.backend.supervisor.Status().PID)
+	return nil
+}
+
+// backendStatus is served on /__govite/status.
+func (app *App) backendStatus() any {
+	if app.backend.supervisor == nil {
+		return supervisorStatus{State: "stopped"}
+	}
+	return app.backend.supervisor.Status()
+}
+
+func (app *App) stopBackend() {
+	if app.backend.supervisor != nil {
+		app.backend.supervisor.Stop()
+	}
+	if app.backend.logs != nil {
+		app.backend.logs.Close()
+	}
+	if app.backend.socketDir != "" {
+		os.RemoveAll(app.backend.socketDir)
+	}
+
+	if app.backend.tempDir != "" {
+		os.RemoveAll(app.backend.tempDir)
+	}
+}
+`
+}
+
+func generateBackendInProcessGo() string {
+	return `package main
+
+import (
+	"os"
+
+	"backend/server"
+	"github.com/gin-gonic/gin"
+)
+
+// backend serves /api requests from the backend's router inside this
+// process, so nothing is extracted and no second process runs.
+type backend struct {
+	router *gin.Engine
+}
+
+// setupBackend builds the backend's router with the same routes the
+// standalone server uses.
+func (app *App) setupBackend() error {
+	app.backend.router = gin.New()
+	app.backend.router.Use(gin.Recovery())
+	server.Mount(app.backend.router)
+	return nil
+}
+
+// serveBackend applies the route timeouts of govite.json, like the sidecar's
+// proxy, by cancelling the request's context. The page's token is removed
+// first, the backend's router and request log have no use for it.
+func (app *App) serveBackend(c *gin.Context) {
+	ctx, cancel := app.manifest.routeContext(c.Request)
+	defer cancel()
+	r := c.Request.Clone(ctx)
+	r.URL.RawQuery = withoutParam(r.URL.RawQuery, tokenParam)
+	r.RequestURI = r.URL.RequestURI()
+	r.Header.Del(tokenHeader)
+	app.backend.router.ServeHTTP(c.Writer, r)
+}
+
+func (app *App) startBackend() error {
+	return nil
+}
+
+// backendStatus is served on /__govite/status.
+func (app *App) backendStatus() any {
+	return gin.H{"state": "running", "mode": "inprocess", "pid": os.Getpid()}
+}
+
+func (app *App) stopBackend() {}
+`
+}
+
+func generateStartupGo() string {
+	return `package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readyLine is what a backend prints on stdout once it is listening. It
+// lets the shell skip polling the health endpoint.
+const readyLine = "GOVITE_READY"
+
+var errBackendExited = errors.New("backend exited during startup")
+
+// waitReady blocks until the backend is ready: it printed readyLine, or
+// healthURL answered 200 through transport (nil for the default). Probes
+// back off from 10ms to 500ms. It fails when exited is closed first or when
+// timeout passes.
+func waitReady(healthURL string, transport http.RoundTripper, ready, exited <-chan struct{}, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	client := &http.Client{Transport: transport, Timeout: time.Second}
+
+	delay := 10 * time.Millisecond
+	for {
+		resp, err := client.Get(healthURL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ready:
+			return nil
+		case <-exited:
+			// A process that announced readiness and then exited was ready
+			select {
+			case <-ready:
+				return nil
+			default:
+				return errBackendExited
+			}
+		case <-deadline.C:
+			return fmt.Errorf("backend not ready after %s", timeout)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, 500*time.Millisecond)
+	}
+}
+
+// readyWriter passes a child's output through to w and closes ready when
+// it sees readyLine.
+type readyWriter struct {
+	w     io.Writer
+	ready chan struct{}
+	once  sync.Once
+	line  []byte
+}
+
+func newReadyWriter(w io.Writer) *readyWriter {
+	return &readyWriter{w: w, ready: make(chan struct{})}
+}
+
+func (rw *readyWriter) Write(p []byte) (int, error) {
+	rw.line = append(rw.line, p...)
+	for {
+		i := bytes.IndexByte(rw.line, '\n')
+		if i < 0 {
+			break
+		}
+		if string(bytes.TrimSpace(rw.line[:i])) == readyLine {
+			rw.once.Do(func() { close(rw.ready) })
+		}
+		rw.line = rw.line[i+1:]
+	}
+	return rw.w.Write(p)
+}
+
+// startupErrorPage is shown in the webview when the app fails to start.
+func startupErrorPage(err error) string {
+	return ` + "`" + `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Failed to start</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 3rem; color: #1f2937; }
+  pre { background: #f3f4f6; padding: 1rem; border-radius: 0.5rem; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>The application failed to start</h1>
+<pre>` + "` + html.EscapeString(err.Error()) + `" + `</pre>
+<p>Restart the application. If this keeps happening, check its log output.</p>
+</body>
+</html>
+` + "`" + `
+}
+`
+}
+
+func generateSupervisorGo() string {
+	return `package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// supervisor runs a child process and restarts it when it exits. Restarts
+// back off exponentially from minBackoff to maxBackoff, and the supervisor
+// gives up once the process has exited more than maxRestarts times within
+// crashWindow.
+type supervisor struct {
+	command      func() *exec.Cmd
+	healthURL    string
+	transport    http.RoundTripper
+	readyTimeout time.Duration
+	// output receives the child's stdout and stderr
+	output      io.Writer
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxRestarts int
+	crashWindow time.Duration
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	exitErr  error
+	status   supervisorStatus
+	crashes  []time.Time
+	stopping bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// supervisorStatus is served on /__govite/status.
+type supervisorStatus struct {
+	// State is starting, running, restarting, failed or stopped
+	State        string    ` + "`json:\"state\"`" + `
+	PID          int       ` + "`json:\"pid\"`" + `
+	Restarts     int       ` + "`json:\"restarts\"`" + `
+	LastExitCode *int      ` + "`json:\"lastExitCode\"`" + `
+	LastError    string    ` + "`json:\"lastError,omitempty\"`" + `
+	StartedAt    time.Time ` + "`json:\"startedAt\"`" + `
+}
+
+var errSupervisorStopped = errors.New("supervisor stopped")
+
+// Start launches the process, waits for it to become ready and then keeps it
+// running until Stop. A process that fails to start is not restarted.
+func (s *supervisor) Start() error {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	if err := s.launch(); err != nil {
+		s.mu.Lock()
+		s.status.State = "failed"
+		s.status.LastError = err.Error()
+		s.mu.Unlock()
+		close(s.done)
+		return err
+	}
+	go s.watch()
+	return nil
+}
+
+// launch starts one process and waits until it is ready. When it returns,
+// s.exited is closed or the process is ready.
+func (s *supervisor) launch() error {
+	cmd := s.command()
+	stdout := newReadyWriter(s.output)
+	cmd.Stdout = stdout
+	cmd.Stderr = s.output
+	exited := make(chan struct{})
+
+	s.mu.Lock()
+	err := errSupervisorStopped
+	if !s.stopping {
+		err = cmd.Start()
+	}
+	if err != nil {
+		// Without a process there is nothing for Stop to signal, so it must
+		// find exited already closed
+		s.cmd, s.exited, s.exitErr = nil, exited, err
+		close(exited)
+		s.mu.Unlock()
+		return err
+	}
+	s.cmd, s.exited, s.exitErr = cmd, exited, nil
+	s.status.State = "starting"
+	s.status.PID = cmd.Process.Pid
+	s.status.StartedAt = time.Now()
+	s.mu.Unlock()
+
+	go func() {
+		err := cmd.Wait()
+		s.mu.Lock()
+		s.exitErr = err
+		s.status.PID = 0
+		s.mu.Unlock()
+		close(exited)
+	}()
+
+	err = waitReady(s.healthURL, s.transport, stdout.ready, exited, s.readyTimeout)
+	if errors.Is(err, errBackendExited) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return fmt.Errorf("%w: %v", err, s.exitErr)
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		<-exited
+		return err
+	}
+
+	s.mu.Lock()
+	s.status.State = "running"
+	s.mu.Unlock()
+	return nil
+}
+
+// watch restarts the process each time it exits, until Stop or until it
+// crashes too often.
+func (s *supervisor) watch() {
+	defer close(s.done)
+	for {
+		s.mu.Lock()
+		exited := s.exited
+		s.mu.Unlock()
+		<-exited
+
+		s.mu.Lock()
+		if s.stopping {
+			s.status.State = "stopped"
+			s.mu.Unlock()
+			return
+		}
+		code := exitCode(s.cmd)
+		s.status.LastExitCode = &code
+		if s.exitErr != nil {
+			s.status.LastError = s.exitErr.Error()
+		}
+
+		now := time.Now()
+		recent := s.crashes[:0]
+		for _, crash := range s.crashes {
+			if now.Sub(crash) < s.crashWindow {
+				recent = append(recent, crash)
+			}
+		}
+		s.crashes = append(recent, now)
+		if len(s.crashes) > s.maxRestarts {
+			s.status.State = "failed"
+			s.mu.Unlock()
+			log.Printf("Backend exited %d times within %s, giving up", len(s.crashes), s.crashWindow)
+			return
+		}
+		delay := s.minBackoff << (len(s.crashes) - 1)
+		if delay > s.maxBackoff || delay <= 0 {
+			delay = s.maxBackoff
+		}
+		s.status.State = "restarting"
+		s.mu.Unlock()
+
+		log.Printf("Backend exited with code %d, restarting in %s", code, delay)
+		select {
+		case <-s.stop:
+			continue
+		case <-time.After(delay):
+		}
+
+		s.mu.Lock()
+		s.status.Restarts++
+		s.mu.Unlock()
+		if err := s.launch(); err != nil && !errors.Is(err, errSupervisorStopped) {
+			log.Printf("Backend restart failed: %v", err)
+		}
+	}
+}
+
+func exitCode(cmd *exec.Cmd) int {
+	if cmd != nil && cmd.ProcessState != nil {
+		return cmd.ProcessState.ExitCode()
+	}
+	return -1
+}
+
+// Stop terminates the process and stops restarting it. A process that does
+// not exit within 10 seconds of SIGTERM is killed.
+func (s *supervisor) Stop() {
+	s.mu.Lock()
+	if s.stopping || s.done == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stopping = true
+	close(s.stop)
+	cmd, exited := s.cmd, s.exited
+	s.mu.Unlock()
+
+	select {
+	case <-exited:
+	default:
+		log.Printf("Stopping backend (PID: %d)", cmd.Process.Pid)
+		cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+		}
+	}
+	<-s.done
+
+	s.mu.Lock()
+	s.status.State = "stopped"
+	s.mu.Unlock()
+}
+
+// Status returns a snapshot of the supervisor's state.
+func (s *supervisor) Status() supervisorStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// rotatingLog appends to a file and rotates it once it grows past maxSize,
+// keeping the keep most recent files as path.1, path.2 and so on.
+type rotatingLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+	size    int64
+}
+
+func openRotatingLog(path string, maxSize int64, keep int) (*rotatingLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	l := &rotatingLog{path: path, maxSize: maxSize, keep: keep}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *rotatingLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+func (l *rotatingLog) rotate() error {
+	l.file.Close()
+	os.Remove(fmt.Sprintf("%s.%d", l.path, l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	if l.keep > 0 {
+		os.Rename(l.path, l.path+".1")
+	} else {
+		os.Remove(l.path)
+	}
+	return l.open()
+}
+
+func (l *rotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+`
+}
+
+func generateProxyGo() string {
+	return `package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backendProxy forwards /api requests to the sidecar backend. It keeps the
+// query string, drops hop-by-hop headers, sets the X-Forwarded headers and
+// the launch token the backend requires, and reuses connections. Streaming
+// responses such as Server-Sent Events are flushed as they arrive, and
+// WebSocket upgrades are passed through. Each request is limited by its
+// route's timeout in govite.json, except upgraded connections, which live as
+// long as both ends keep them open.
+type backendProxy struct {
+	proxy    *httputil.ReverseProxy
+	manifest *Manifest
+}
+
+func newBackendProxy(target *url.URL, transport http.RoundTripper, token string, manifest *Manifest) *backendProxy {
+	return &backendProxy{
+		proxy: &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(target)
+				// SetURL re-encodes the query, keep it byte for byte. The
+				// page's token stays with the shell, where the backend's
+				// request log cannot record it, and the shell sends its own.
+				r.Out.URL.RawQuery = withoutParam(r.In.URL.RawQuery, tokenParam)
+				r.SetXForwarded()
+				r.Out.Header.Set(tokenHeader, token)
+			},
+			Transport:    transport,
+			ErrorHandler: proxyError,
+		},
+		manifest: manifest,
+	}
+}
+
+// newBackendTransport returns the connection pool shared by all proxied
+// requests. With a socket path every connection dials that Unix socket,
+// whatever the request's host. Environment proxies are ignored, the backend
+// is local.
+func newBackendTransport(socket string) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	dial := dialer.DialContext
+	if socket != "" {
+		dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+	return &http.Transport{
+		DialContext:         dial,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// newSocketDir creates a private directory for the backend's Unix socket,
+// under $XDG_RUNTIME_DIR when it is set, and checks that a socket can be
+// created there. The caller removes dir.
+func newSocketDir() (dir, socket string, err error) {
+	dir, err = os.MkdirTemp(os.Getenv("XDG_RUNTIME_DIR"), "govite-")
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	socket = filepath.Join(dir, "backend.sock")
+	probe, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	probe.Close()
+	os.Remove(socket)
+	return dir, socket, nil
+}
+
+func (p *backendProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := p.manifest.routeContext(r)
+	defer cancel()
+	p.proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func proxyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) {
+		// The client went away
+		return
+	}
+	log.Printf("Proxy error for %s %s: %v", r.Method, r.URL.Path, err)
+	status, message := http.StatusBadGateway, "Backend service unavailable"
+	if errors.Is(err, context.DeadlineExceeded) {
+		status, message = http.StatusGatewayTimeout, "Backend timed out"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+`
+}
+
+func generateGuardGo() string {
+	return `package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// tokenHeader carries the per-launch token on /api requests. EventSource
+// and WebSocket cannot set headers, so they pass it as ?govite_token=.
+const (
+	tokenHeader = "X-Govite-Token"
+	tokenParam  = "govite_token"
+)
+
+// newToken returns a random token for this launch of the app.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// withoutParam removes every name parameter from a raw query and leaves the
+// rest of it as it was. It keeps the page's token out of what the backend
+// sees and logs.
+func withoutParam(rawQuery, name string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var kept []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil && unescaped == name {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return strings.Join(kept, "&")
+}
+
+// tokenScript is injected into every document before it loads. In the app's
+// own document it exposes the token as window.__govite.token and adds it to
+// same-origin /api and /__govite requests made with fetch or XMLHttpRequest.
+// Any other page the webview shows gets nothing.
+func tokenScript(token, appOrigin string) string {
+	quoted, _ := json.Marshal(token)
+	return ` + "`" + `(() => {
+  if (!` + "` + appDocument(appOrigin) + `" + `) return;
+  const token = ` + "` + string(quoted) + `" + `;
+  const govite = (window.__govite = window.__govite || {});
+  govite.token = token;
+  const needsToken = (url) => {
+    try {
+      const { origin, pathname } = new URL(url, location.href);
+      return origin === location.origin && (pathname.startsWith("/api/") || pathname.startsWith("/__govite/"));
+    } catch {
+      return false;
+    }
+  };
+
+  const nativeFetch = window.fetch.bind(window);
+  window.fetch = (input, init) => {
+    if (!needsToken(input instanceof Request ? input.url : String(input))) {
+      return nativeFetch(input, init);
+    }
+    const request = new Request(input, init);
+    request.headers.set("` + "` + tokenHeader + `" + `", token);
+    return nativeFetch(request);
+  };
+
+  const open = XMLHttpRequest.prototype.open;
+  const send = XMLHttpRequest.prototype.send;
+  XMLHttpRequest.prototype.open = function (method, url, ...rest) {
+    this.__goviteToken = needsToken(url);
+    return open.call(this, method, url, ...rest);
+  };
+  XMLHttpRequest.prototype.send = function (body) {
+    if (this.__goviteToken) {
+      this.setRequestHeader("` + "` + tokenHeader + `" + `", token);
+    }
+    return send.call(this, body);
+  };
+})();
+` + "`" + `
+}
+
+// requestGuard admits only requests meant for this app from its own page.
+// Host must be the address the app is served on, which stops DNS
+// rebinding. An Origin header must be the app's origin, which stops other
+// pages from driving the app. Requests that need the token must carry it.
+type requestGuard struct {
+	token  string
+	host   string
+	origin string
+}
+
+// callerOrigin is the origin a binding call is attributed to. Only the app's
+// own document is given the token, so a call without it has no origin.
+func (g *requestGuard) callerOrigin(secret string) string {
+	if g.token != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(g.token)) == 1 {
+		return g.origin
+	}
+	return ""
+}
+
+// appDocument is a JavaScript expression that is true only in the app's own
+// document. Init scripts run in every document the webview loads, including
+// foreign pages after a navigation, so they use it to keep the token to the
+// app. Pages loaded with SetHtml have an opaque origin and no URL.
+func appDocument(appOrigin string) string {
+	if appOrigin == "null" {
+		return ` + "`" + `(location.origin === "null" && location.href === "about:blank")` + "`" + `
+	}
+	if appOrigin == "" {
+		return "false"
+	}
+	quoted, _ := json.Marshal(appOrigin)
+	return "(location.origin === " + string(quoted) + ")"
+}
+
+var (
+	errBadHost   = errors.New("unexpected Host header")
+	errBadOrigin = errors.New("unexpected Origin header")
+	errBadToken  = errors.New("missing or invalid app token")
+)
+
+// check returns the status and error to reject r with, or nil.
+func (g *requestGuard) check(r *http.Request, needToken bool) (int, error) {
+	if r.Host != g.host {
+		return http.StatusForbidden, errBadHost
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && origin != g.origin {
+		return http.StatusForbidden, errBadOrigin
+	}
+	if needToken {
+		token := r.Header.Get(tokenHeader)
+		if token == "" {
+			token = r.URL.Query().Get(tokenParam)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(g.token)) != 1 {
+			return http.StatusUnauthorized, errBadToken
+		}
+	}
+	return 0, nil
+}
+`
+}
+
+func generateBindingsGo() string {
+	return `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+
+	"github.com/webview/webview_go"
+)
+
+// Service holds the Go methods the frontend calls directly, without a round
+// trip through the /api proxy. Every exported method is bound into the
+// webview as window.go_<Method>. Arguments and results travel as JSON, and a
+// returned error rejects the JavaScript promise. A method that takes a
+// context.Context first receives the caller's origin in it, see CallerOrigin.
+// A webview cannot prove which page is calling, so the origin is either the
+// app's own or empty, and bindings granted to other origins are never
+// callable.
+//
+// Only bindings granted in govite.json can be called. Run
+// ` + "`go-vite gen bindings`" + ` after changing Service to regenerate the typed
+// wrappers in frontend/src/bindings.ts.
+type Service struct {
+	app *App
+}
+
+// AppInfo describes the running application.
+type AppInfo struct {
+	Version   string ` + "`json:\"version\"`" + `
+	BuildTime string ` + "`json:\"buildTime\"`" + `
+	GitCommit string ` + "`json:\"gitCommit\"`" + `
+	Platform  string ` + "`json:\"platform\"`" + `
+}
+
+// Info returns the version and platform of the application.
+func (s *Service) Info() AppInfo {
+	return AppInfo{
+		Version:   Version,
+		BuildTime: BuildTime,
+		GitCommit: GitCommit,
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// Greet returns a greeting for name.
+func (s *Service) Greet(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	return fmt.Sprintf("Hello, %s!", name), nil
+}
+
+// ReadTextFile returns the contents of a file inside a filesystem scope
+// granted to the caller.
+func (s *Service) ReadTextFile(ctx context.Context, path string) (string, error) {
+	if err := s.checkPath(ctx, path, false); err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// checkPath returns an error unless the caller may read, or with write also
+// modify, path.
+func (s *Service) checkPath(ctx context.Context, path string, write bool) error {
+	origin := CallerOrigin(ctx)
+	if !s.app.policy.AllowPath(origin, path, write) {
+		s.app.deny(origin, "filesystem access to "+path)
+		return fmt.Errorf("access to %s is not allowed", path)
+	}
+	return nil
+}
+
+// bindingShim runs before the page's own scripts. It takes the raw bindings
+// off window, so pages cannot call them directly, and exposes each one as
+// window.go_<Method>. Only the app's own document gets the launch token to
+// pass along, so Go can tell its calls from those of any other page the
+// webview ends up showing.
+const bindingShim = ` + "`" + `(() => {
+  const secret = %[2]s ? %[3]s : "";
+  for (const name of %[1]s) {
+    const native = window["__go_" + name];
+    delete window["__go_" + name];
+    window["go_" + name] = (...args) => native(secret, args);
+  }
+})();` + "`" + `
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// bindService binds every exported method of svc into the webview. Each call
+// is checked against the app's capability policy before the method runs. The
+// caller's origin is decided here, never taken from the page: calls with the
+// launch token come from the app, and any other call has no origin.
+func bindService(w webview.WebView, app *App, svc interface{}) error {
+	value := reflect.ValueOf(svc)
+	var names []string
+	for i := 0; i < value.NumMethod(); i++ {
+		name := value.Type().Method(i).Name
+		method := value.Method(i)
+		call := func(secret string, args []json.RawMessage) (interface{}, error) {
+			origin := app.guard.callerOrigin(secret)
+			if !app.policy.AllowBinding(origin, name) {
+				app.deny(origin, "binding "+name)
+				return nil, fmt.Errorf("binding %s is not allowed for %s", name, origin)
+			}
+			return callMethod(method, withOrigin(context.Background(), origin), args)
+		}
+		if err := w.Bind("__go_"+name, call); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", name, err)
+		}
+		names = append(names, name)
+	}
+
+	list, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	secret, err := json.Marshal(app.token)
+	if err != nil {
+		return err
+	}
+	w.Init(fmt.Sprintf(bindingShim, list, appDocument(app.guard.origin), secret))
+	return nil
+}
+
+// callMethod decodes the JSON arguments into the method's parameters, calls
+// it and returns its value and error.
+func callMethod(method reflect.Value, ctx context.Context, args []json.RawMessage) (interface{}, error) {
+	typ := method.Type()
+	var in []reflect.Value
+	if typ.NumIn() > 0 && typ.In(0) == contextType {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+	if len(args) != typ.NumIn()-len(in) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", typ.NumIn()-len(in), len(args))
+	}
+	for _, arg := range args {
+		param := reflect.New(typ.In(len(in)))
+		if err := json.Unmarshal(arg, param.Interface()); err != nil {
+			return nil, fmt.Errorf("argument %d: %w", len(in)+1, err)
+		}
+		in = append(in, param.Elem())
+	}
+
+	out := method.Call(in)
+	if n := len(out); n > 0 && typ.Out(n-1) == errorType {
+		if err, _ := out[n-1].Interface().(error); err != nil {
+			return nil, err
+		}
+		out = out[:n-1]
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out[0].Interface(), nil
+}
+`
+}
+
+func generateBridgeGo() string {
+	return `package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The bridge transport tunnels the page's fetch calls through a webview
+// binding into the app's http.Handler, so the shell needs no listening port.
+// bridgeShim replaces window.fetch for app paths ("/..."), and bridgePage
+// boots the app by loading index.html, its stylesheets and its scripts
+// through the bridge. The page is about:blank, where the browser cannot load
+// app paths itself: images, icons and CSS url()s in index.html and its
+// stylesheets are fetched through the bridge and replaced by blob URLs, and
+// app code does the same for elements it creates with __govite.assetURL.
+// import() of app paths is not tunnelled, the bridge build inlines dynamic
+// imports instead. Responses are buffered, so streaming and WebSockets need
+// the http transport. The shim takes the raw binding off window and is only
+// installed in the app's own document, see bridgeScript.
+const bridgeShim = ` + "`" + `(() => {
+  const bridgeFetch = window.__govite_fetch;
+  delete window.__govite_fetch;
+  if (!%[1]s) return;
+  const secret = %[2]s;
+  const nativeFetch = window.fetch.bind(window);
+  const govite = (window.__govite = window.__govite || {});
+
+  window.fetch = async (input, init = {}) => {
+    if (typeof input !== "string" || !input.startsWith("/") || input.startsWith("//")) {
+      return nativeFetch(input, init);
+    }
+    const headers = {};
+    new Headers(init.headers || {}).forEach((value, key) => {
+      headers[key] = value;
+    });
+    let body = "";
+    if (init.body != null) {
+      const encoded = new Response(init.body);
+      if (!headers["content-type"] && encoded.headers.get("content-type")) {
+        headers["content-type"] = encoded.headers.get("content-type");
+      }
+      const bytes = new Uint8Array(await encoded.arrayBuffer());
+      body = btoa(Array.from(bytes, (b) => String.fromCharCode(b)).join(""));
+    }
+    const res = await bridgeFetch(secret, {
+      method: (init.method || "GET").toUpperCase(),
+      url: input,
+      headers,
+      body,
+    });
+    const bytes = Uint8Array.from(atob(res.body), (c) => c.charCodeAt(0));
+    const empty = res.status === 204 || res.status === 304;
+    return new Response(empty ? null : bytes, { status: res.status, headers: res.headers });
+  };
+
+  const isAppPath = (url) => typeof url === "string" && url.startsWith("/") && !url.startsWith("//");
+  const assetURLs = new Map();
+  govite.assetURL = (url) => {
+    if (!isAppPath(url)) return Promise.resolve(url);
+    if (!assetURLs.has(url)) {
+      assetURLs.set(url, window.fetch(url).then(async (res) => {
+        if (!res.ok) throw new Error(url + ": " + res.status);
+        return URL.createObjectURL(await res.blob());
+      }));
+    }
+    return assetURLs.get(url);
+  };
+  const withAssetURLs = async (css) => {
+    let out = "";
+    let last = 0;
+    for (const match of css.matchAll(/url\(\s*(["']?)(\/[^/"')][^"')]*)\1\s*\)/g)) {
+      out += css.slice(last, match.index) + 'url("' + (await govite.assetURL(match[2])) + '")';
+      last = match.index + match[0].length;
+    }
+    return out + css.slice(last);
+  };
+
+  govite.load = async () => {
+    const text = async (url) => (await window.fetch(url)).text();
+    const doc = new DOMParser().parseFromString(await text("/"), "text/html");
+    for (const link of doc.querySelectorAll('link[rel="stylesheet"][href]')) {
+      const style = doc.createElement("style");
+      style.textContent = await text(link.getAttribute("href"));
+      link.replaceWith(style);
+    }
+    for (const style of doc.querySelectorAll("style")) {
+      style.textContent = await withAssetURLs(style.textContent);
+    }
+    for (const el of doc.querySelectorAll('img[src], source[src], video[src], audio[src], link[rel~="icon"][href]')) {
+      const attr = el.hasAttribute("src") ? "src" : "href";
+      el.setAttribute(attr, await govite.assetURL(el.getAttribute(attr)));
+    }
+    const scripts = [...doc.querySelectorAll("script")];
+    scripts.forEach((script) => script.remove());
+    document.replaceChild(document.importNode(doc.documentElement, true), document.documentElement);
+    for (const original of scripts) {
+      const script = document.createElement("script");
+      for (const attr of original.attributes) {
+        if (attr.name !== "src") script.setAttribute(attr.name, attr.value);
+      }
+      const src = original.getAttribute("src");
+      script.textContent = src ? await text(src) : original.textContent;
+      document.head.appendChild(script);
+    }
+  };
+})();` + "`" + `
+
+const bridgePage = ` + "`" + `<!doctype html>
+<html>
+  <head><meta charset="utf-8" /></head>
+  <body>
+    <script>
+      window.__govite.load().catch((err) => {
+        document.body.textContent = "Failed to load the application: " + err;
+      });
+    </script>
+  </body>
+</html>` + "`" + `
+
+// bridgeRequest is a fetch call tunnelled from the page. Bodies are base64.
+type bridgeRequest struct {
+	Method  string            ` + "`json:\"method\"`" + `
+	URL     string            ` + "`json:\"url\"`" + `
+	Headers map[string]string ` + "`json:\"headers\"`" + `
+	Body    string            ` + "`json:\"body\"`" + `
+}
+
+type bridgeResponse struct {
+	Status  int               ` + "`json:\"status\"`" + `
+	Headers map[string]string ` + "`json:\"headers\"`" + `
+	Body    string            ` + "`json:\"body\"`" + `
+}
+
+// memoryResponse collects a response in memory.
+type memoryResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *memoryResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *memoryResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *memoryResponse) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+func (r *memoryResponse) Flush() {}
+
+// bridgeScript is bridgeShim for this launch. The app's document passes the
+// launch token with every request, which no other page can do.
+func bridgeScript(appOrigin, token string) string {
+	secret, _ := json.Marshal(token)
+	return fmt.Sprintf(bridgeShim, appDocument(appOrigin), secret)
+}
+
+// bridgeCall serves a __govite_fetch call. The page cannot choose the origin:
+// a call with the launch token comes from the app, gets the app's origin and
+// passes the request guard, and any other call is refused.
+func bridgeCall(handler http.Handler, guard *requestGuard, secret string, req bridgeRequest) (bridgeResponse, error) {
+	origin := guard.callerOrigin(secret)
+	if origin == "" {
+		return bridgeResponse{}, errors.New("the bridge only serves the app's own page")
+	}
+	headers := map[string]string{tokenHeader: guard.token}
+	for key, value := range req.Headers {
+		if !strings.EqualFold(key, tokenHeader) {
+			headers[key] = value
+		}
+	}
+	req.Headers = headers
+	return serveBridge(handler, origin, req)
+}
+
+// serveBridge runs a tunnelled request through handler. The request carries
+// the app's origin, so the capability policy applies as it does over HTTP.
+func serveBridge(handler http.Handler, origin string, req bridgeRequest) (bridgeResponse, error) {
+	if !strings.HasPrefix(req.URL, "/") || strings.HasPrefix(req.URL, "//") {
+		return bridgeResponse{}, fmt.Errorf("the bridge only serves app paths, got %q", req.URL)
+	}
+	body, err := base64.StdEncoding.DecodeString(req.Body)
+	if err != nil {
+		return bridgeResponse{}, fmt.Errorf("invalid request body: %w", err)
+	}
+	httpReq, err := http.NewRequest(req.Method, "http://bridge"+req.URL, bytes.NewReader(body))
+	if err != nil {
+		return bridgeResponse{}, err
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpReq.Header.Set("Origin", origin)
+	httpReq.RemoteAddr = "bridge"
+
+	resp := &memoryResponse{header: make(http.Header)}
+	handler.ServeHTTP(resp, httpReq)
+	resp.WriteHeader(http.StatusOK)
+
+	headers := make(map[string]string, len(resp.header))
+	for key, values := range resp.header {
+		headers[key] = strings.Join(values, ", ")
+	}
+	return bridgeResponse{
+		Status:  resp.status,
+		Headers: headers,
+		Body:    base64.StdEncoding.EncodeToString(resp.body.Bytes()),
+	}, nil
+}
+`
+}
+
+func generateCapabilitiesGo() string {
+	return `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Manifest is govite.json, embedded into the binary. Transport is how the
+// webview reaches the app: "http" (the default) over a local port, or
+// "bridge" through a webview binding. Its capabilities grant origins access
+// to native bindings, filesystem scopes and backend routes. Anything not
+// granted is denied. Timeouts limit how long backend routes may take.
+type Manifest struct {
+	Name         string         ` + "`json:\"name\"`" + `
+	Transport    string         ` + "`json:\"transport\"`" + `
+	Capabilities []Capability   ` + "`json:\"capabilities\"`" + `
+	Timeouts     []RouteTimeout ` + "`json:\"timeouts\"`" + `
+}
+
+// Capability grants its origins the listed bindings, filesystem scopes and
+// routes. The origin "app" is the embedded frontend and "*" is any origin.
+// Bindings are method names and may use path.Match wildcards. Routes are
+// "[METHOD] /path" patterns, where a trailing /** matches any subpath.
+type Capability struct {
+	Origins  []string  ` + "`json:\"origins\"`" + `
+	Bindings []string  ` + "`json:\"bindings\"`" + `
+	FS       []FSScope ` + "`json:\"fs\"`" + `
+	Routes   []string  ` + "`json:\"routes\"`" + `
+}
+
+// RouteTimeout limits requests to the routes matching Route, a pattern like
+// those of Capability.Routes. Timeout is a Go duration such as "30s" or
+// "5m", and "0" means no limit.
+type RouteTimeout struct {
+	Route   string ` + "`json:\"route\"`" + `
+	Timeout string ` + "`json:\"timeout\"`" + `
+}
+
+// defaultRouteTimeout applies to routes no timeout matches.
+const defaultRouteTimeout = 30 * time.Second
+
+// FSScope grants access to a directory tree. Path may start with $APPDATA,
+// $HOME or $TEMP. Access is "read" or "readwrite".
+type FSScope struct {
+	Path   string ` + "`json:\"path\"`" + `
+	Access string ` + "`json:\"access\"`" + `
+}
+
+const appOriginName = "app"
+
+const (
+	transportHTTP   = "http"
+	transportBridge = "bridge"
+)
+
+// LoadManifest parses and checks govite.json.
+func LoadManifest(data []byte) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid govite.json: %w", err)
+	}
+	switch manifest.Transport {
+	case "":
+		manifest.Transport = transportHTTP
+	case transportHTTP, transportBridge:
+	default:
+		return nil, fmt.Errorf("invalid govite.json: unknown transport %q", manifest.Transport)
+	}
+	for i, capability := range manifest.Capabilities {
+		if len(capability.Origins) == 0 {
+			return nil, fmt.Errorf("invalid govite.json: capability %d has no origins", i+1)
+		}
+		for _, scope := range capability.FS {
+			if scope.Access != "read" && scope.Access != "readwrite" {
+				return nil, fmt.Errorf("invalid govite.json: access of %s must be read or readwrite", scope.Path)
+			}
+		}
+		for _, route := range capability.Routes {
+			if _, _, err := parseRoute(route); err != nil {
+				return nil, fmt.Errorf("invalid govite.json: %w", err)
+			}
+		}
+	}
+	for _, timeout := range manifest.Timeouts {
+		if _, _, err := parseRoute(timeout.Route); err != nil {
+			return nil, fmt.Errorf("invalid govite.json: %w", err)
+		}
+		if d, err := time.ParseDuration(timeout.Timeout); err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid govite.json: invalid timeout %q for %s", timeout.Timeout, timeout.Route)
+		}
+	}
+	return manifest, nil
+}
+
+// RouteTimeout is how long a request to the backend may take. The first
+// matching entry of Timeouts wins, and 0 means no limit.
+func (m *Manifest) RouteTimeout(method, requestPath string) time.Duration {
+	for _, timeout := range m.Timeouts {
+		routeMethod, pattern, _ := parseRoute(timeout.Route)
+		if (routeMethod == "*" || routeMethod == method) && matchRoute(pattern, requestPath) {
+			d, _ := time.ParseDuration(timeout.Timeout)
+			return d
+		}
+	}
+	return defaultRouteTimeout
+}
+
+// routeContext is the context a backend request runs with, limited by its
+// route's timeout. Upgraded connections are not limited, they live as long
+// as both ends keep them open.
+func (m *Manifest) routeContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if !isUpgrade(r) {
+		if timeout := m.RouteTimeout(r.Method, r.URL.Path); timeout > 0 {
+			return context.WithTimeout(r.Context(), timeout)
+		}
+	}
+	return context.WithCancel(r.Context())
+}
+
+func isUpgrade(r *http.Request) bool {
+	if r.Header.Get("Upgrade") == "" {
+		return false
+	}
+	for _, token := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// AppDataDir is where the application keeps its data.
+func (m *Manifest) AppDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, m.Name)
+}
+
+// Policy answers whether an origin may use a binding, path or route.
+type Policy struct {
+	manifest *Manifest
+	// AppOrigin is the origin the embedded frontend is served from.
+	AppOrigin string
+}
+
+func NewPolicy(manifest *Manifest, appOrigin string) *Policy {
+	return &Policy{manifest: manifest, AppOrigin: appOrigin}
+}
+
+// granted returns the capabilities that apply to origin.
+func (p *Policy) granted(origin string) []Capability {
+	var granted []Capability
+	for _, capability := range p.manifest.Capabilities {
+		for _, o := range capability.Origins {
+			if o == "*" || o == origin || (o == appOriginName && origin != "" && origin == p.AppOrigin) {
+				granted = append(granted, capability)
+				break
+			}
+		}
+	}
+	return granted
+}
+
+func (p *Policy) AllowBinding(origin, name string) bool {
+	for _, capability := range p.granted(origin) {
+		for _, pattern := range capability.Bindings {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Policy) AllowRoute(origin, method, requestPath string) bool {
+	for _, capability := range p.granted(origin) {
+		for _, route := range capability.Routes {
+			routeMethod, pattern, _ := parseRoute(route)
+			if (routeMethod == "*" || routeMethod == method) && matchRoute(pattern, requestPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowPath reports whether origin may read name, or with write also modify
+// it. Symlinks are resolved first, so a link cannot lead out of a scope.
+func (p *Policy) AllowPath(origin, name string, write bool) bool {
+	target := resolvePath(name)
+	for _, capability := range p.granted(origin) {
+		for _, scope := range capability.FS {
+			if write && scope.Access != "readwrite" {
+				continue
+			}
+			rel, err := filepath.Rel(resolvePath(p.expand(scope.Path)), target)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Policy) expand(scope string) string {
+	home, _ := os.UserHomeDir()
+	for prefix, dir := range map[string]string{
+		"$APPDATA": p.manifest.AppDataDir(),
+		"$HOME":    home,
+		"$TEMP":    os.TempDir(),
+	} {
+		if scope == prefix || strings.HasPrefix(scope, prefix+"/") {
+			return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(scope, prefix)))
+		}
+	}
+	return filepath.FromSlash(scope)
+}
+
+// resolvePath returns the absolute path of name with symlinks resolved. A
+// file that does not exist yet is resolved through its directory.
+func resolvePath(name string) string {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return name
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		return filepath.Join(dir, filepath.Base(abs))
+	}
+	return abs
+}
+
+func parseRoute(route string) (method, pattern string, err error) {
+	fields := strings.Fields(route)
+	switch len(fields) {
+	case 1:
+		method, pattern = "*", fields[0]
+	case 2:
+		method, pattern = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return "", "", fmt.Errorf("invalid route %q", route)
+	}
+	if !strings.HasPrefix(pattern, "/") {
+		return "", "", fmt.Errorf("route %q must start with /", route)
+	}
+	return method, pattern, nil
+}
+
+func matchRoute(pattern, requestPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return requestPath == prefix || strings.HasPrefix(requestPath, prefix+"/")
+	}
+	ok, _ := path.Match(pattern, requestPath)
+	return ok
+}
+
+// requestOrigin returns the origin of the page that sent r, from its Origin
+// header or else its Referer. Opaque origins are "null".
+func requestOrigin(r *http.Request) string {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return origin
+	}
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Host != "" {
+		return referer.Scheme + "://" + referer.Host
+	}
+	return ""
+}
+
+type originKey struct{}
+
+func withOrigin(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// CallerOrigin returns the origin of the page that made a binding call.
+func CallerOrigin(ctx context.Context) string {
+	origin, _ := ctx.Value(originKey{}).(string)
+	return origin
+}
+`
+}
+
+func generateManifest(config Config) string {
+	transport := config.Transport
+	if transport == "" {
+		transport = TransportHTTP
+	}
+	return fmt.Sprintf(`{
+  "name": %s,
+  "transport": %s,
+  "capabilities": [
+    {
+      "origins": ["app"],
+      "bindings": ["Info", "Greet", "ReadTextFile"],
+      "fs": [{ "path": "$APPDATA", "access": "readwrite" }],
+      "routes": ["/api/**"]
+    }
+  ],
+  "timeouts": [
+    { "route": "/api/**", "timeout": "30s" }
+  ]
+}
+`, jsonString(config.Name), jsonString(transport))
+}
+
+// generateBindingsTs renders the TypeScript wrappers for the generated
+// bindings.go, the same output `go-vite gen bindings` produces.
+func generateBindingsTs() string {
+	ts, err := Bindings(map[string]string{"bindings.go": generateBindingsGo()})
+	if err != nil {
+		panic("generator: invalid bindings.go template: " + err.Error())
+	}
+	return ts
+}
+
+func generateMakefile(config Config) string {
+	// An in-process backend is compiled into the binary, not embedded
+	binaryDeps := "frontend backend"
+	if config.Backend == BackendInProcess {
+		binaryDeps = "frontend"
+	}
+	return fmt.Sprintf(`# %s Build System
+PROJECT_NAME := %s
+VERSION := $(shell git describe --tags --always --dirty 2>/dev/null || echo "dev")
+BUILD_TIME := $(shell date -u +"%%Y-%%m-%%dT%%H:%%M:%%SZ")
+GIT_COMMIT := $(shell git rev-parse --short HEAD 2>/dev/null || echo "unknown")
+
+ROOT_DIR := $(shell pwd)
+BACKEND_DIR := $(ROOT_DIR)/backend
+FRONTEND_DIR := $(ROOT_DIR)/frontend
+BUILD_DIR := $(ROOT_DIR)/build
+DIST_DIR := $(ROOT_DIR)/dist
+
+GOOS := $(shell go env GOOS)
+GOARCH := $(shell go env GOARCH)
+CGO_ENABLED := 0
+CGO_ENABLED_WEBVIEW := 1
+
+LDFLAGS := -X main.Version=$(VERSION) \
+           -X main.BuildTime=$(BUILD_TIME) \
+           -X main.GitCommit=$(GIT_COMMIT) \
+           -w -s
+
+NPM_CMD := npm
+
+.PHONY: help all clean build test deps frontend backend binary
+
+all: clean deps frontend backend binary
+
+help:
+	@echo "Available targets:"
+	@echo "  all       - Build everything"
+	@echo "  deps      - Install dependencies"
+	@echo "  frontend  - Build React frontend"
+	@echo "  backend   - Build Go backend"
+	@echo "  binary    - Build unified binary"
+	@echo "  clean     - Clean build artifacts"
+	@echo "  test      - Run tests"
+
+deps: deps-go deps-node
+
+deps-go:
+	@echo "Installing Go dependencies..."
+	cd $(BACKEND_DIR) && go mod download && go mod tidy
+	go mod download
+
+deps-node:
+	@echo "Installing Node.js dependencies..."
+	cd $(FRONTEND_DIR) && $(NPM_CMD) install
+
+frontend: deps-node
+	@echo "Building React frontend..."
+	cd $(FRONTEND_DIR) && $(NPM_CMD) run build
+	@echo "Frontend build completed"
+
+backend: deps-go
+	@echo "Building Go backend..."
+	cd $(BACKEND_DIR) && go build -ldflags "$(LDFLAGS)" -o bin/backend ./cmd/server
+	@mkdir -p bin
+	cp $(BACKEND_DIR)/bin/backend bin/
+	@echo "Backend build completed"
+
+binary: %s
+	@echo "Creating unified binary..."
+	@mkdir -p $(DIST_DIR)
+	CGO_ENABLED=$(CGO_ENABLED_WEBVIEW) go build -ldflags "$(LDFLAGS)" -o $(DIST_DIR)/%s .
+	@echo "Unified binary created:" $(DIST_DIR)/%s
+
+clean:
+	@echo "Cleaning build artifacts..."
+	rm -rf $(BUILD_DIR)
+	rm -rf $(DIST_DIR)
+	rm -rf $(BACKEND_DIR)/bin
+	rm -rf bin
+
+test:
+	@echo "Running tests..."
+	cd $(BACKEND_DIR) && go test -v ./...
+
+run: binary
+	./$(DIST_DIR)/%s
+`, singleLine(config.Name), makeValue(config.Name), binaryDeps, makeShellArg(config.Name), makeShellArg(config.Name), makeShellArg(config.Name))
+}
+
+func generateReadme(config Config) string {
+	return fmt.Sprintf(`# %s
+
+%s
+
+## 🚀 Quick Start
+
+### Prerequisites
+- Go 1.24+
+- Node.js 18+
+- npm/yarn
+
+### Installation & Running
+
+`+"```"+`bash
+# Install dependencies
+make deps
+
+# Build the application
+make binary
+
+# Run the desktop application
+./dist/%s
+`+"```"+`
+
+## 📁 Project Structure
+
+`+"```"+`
+%s/
+├── main.go                    # Desktop app entry point
+├── Makefile                   # Build automation
+├── backend/                   # Go backend service
+│   ├── cmd/server/           # Backend server entry
+│   ├── config/               # Configuration
+│   ├── internal/
+│   │   ├── api/             # API routes and handlers
+│   │   ├── models/          # Data models
+│   │   ├── modules/         # Business logic modules
+│   │   ├── storage/         # Database and cache
+│   │   └── utils/           # Utilities
+│   └── tests/               # Tests
+├── frontend/                  # React frontend
+│   ├── src/
+│   │   ├── components/      # React components
+│   │   ├── pages/           # Page components
+│   │   ├── hooks/           # Custom hooks
+│   │   ├── services/        # API services
+│   │   └── utils/           # Utilities
+│   ├── index.html
+│   ├── package.json
+│   └── vite.config.js
+└── dist/                      # Final binary output
+`+"```"+`
+
+## 🛠️ Development
+
+`+"```"+`bash
+# Run frontend dev server
+cd frontend && npm run dev
+
+# Run backend dev server
+cd backend && go run ./cmd/server
+
+# Build for production
+make all
+`+"```"+`
+
+## 📄 License
+
+MIT License
+`, singleLine(config.Name), config.Description, config.Name, config.Name)
+}
+
+func generateGitignore() string {
+	return `# Dependencies
+node_modules/
+vendor/
+
+# Build outputs
+dist/
+build/
+*.exe
+*.dll
+*.so
+*.dylib
+
+# Environment files
+.env
+.env.local
+.env.production
+.env.development
+
+# Logs
+*.log
+logs/
+
+# Editor directories
+.vscode/
+.idea/
+*.swp
+
+# Testing
+coverage/
+
+# Backend specific
+backend/tmp/
+backend/bin/
+*.test
+
+# Database
+*.db
+*.sqlite
+data/
+
+# OS
+.DS_Store
+Thumbs.db
+
+bin/*
+`
+}
+
+func generateGitattributes() string {
+	return `* text=auto
+`
+}
+
+func generateEnvExample(config Config) string {
+	return fmt.Sprintf(`# Frontend
+VITE_API_URL=http://localhost:%d
+VITE_APP_NAME=%s
+
+# Backend (HOST=0.0.0.0 exposes it to the network)
+HOST=127.0.0.1
+PORT=%d
+LOG_LEVEL=info
+`, config.BackendPort, singleLine(config.Name), config.BackendPort)
+}
+
+func generateNetlifyToml(config Config) string {
+	return fmt.Sprintf(`[build]
+  publish = "frontend/dist"
+  functions = "netlify/functions"
+
+[build.environment]
+  NODE_VERSION = "18"
+  VITE_APP_NAME = %s
+  VITE_API_URL = "http://localhost:%d"
+
+[[redirects]]
+  from = "/api/*"
+  to = "/.netlify/functions/api/:splat"
+  status = 200
+
+[[redirects]]
+  from = "/*"
+  to = "/index.html"
+  status = 200
+`, tomlString(config.Name), config.BackendPort)
+}
+
+func generateNetlifyApiFunction() string {
+	return `const axios = require('axios');
+
+exports.handler = async (event, context) => {
+  // Set CORS headers
+  const headers = {
+    'Access-Control-Allow-Origin': '*',
+    'Access-Control-Allow-Headers': 'Content-Type',
+    'Access-Control-Allow-Methods': 'GET, POST, PUT, DELETE, OPTIONS',
+    'Content-Type': 'application/json'
+  };
+
+  // Handle preflight requests
+  if (event.httpMethod === 'OPTIONS') {
+    return {
+      statusCode: 200,
+      headers,
+      body: ''
+    };
+  }
+
+  try {
+    // Extract the path after /api/
+    const path = event.path.replace('/.netlify/functions/api/', '');
+
+    // Forward the request to your backend service
+    // In production, replace this with your actual backend URL
+    const backendUrl = process.env.BACKEND_URL || 'http://localhost:8080';
+
+    const response = await axios({
+      method: event.httpMethod,
+      url: backendUrl + '/api/' + path,
+      data: event.body,
+      headers: {
+        'Content-Type': event.headers['content-type'] || 'application/json',
+        'Authorization': event.headers.authorization || '',
+      },
+      params: event.queryStringParameters
+    });
+
+    return {
+      statusCode: response.status,
+      headers,
+      body: JSON.stringify(response.data)
+    };
+
+  } catch (error) {
+    console.error('API proxy error:', error);
+
+    return {
+      statusCode: error.response?.status || 500,
+      headers,
+      body: JSON.stringify({
+        error: 'Internal Server Error',
+        message: error.message
+      })
+    };
+  }
+};
+`
+}
+
+// Backend generators
+func generateBackendGoMod() string {
+	return `module backend
+
+go 1.24
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/joho/godotenv v1.5.1
+)
+`
+}
+
+func generateBackendMain() string {
+	return `package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"backend/config"
+	"backend/server"
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	if err := config.LoadConfig(); err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	router := gin.Default()
+	// The desktop shell passes its launch token and the host it proxies to.
+	// Standalone runs, such as behind the Vite dev server, are not guarded.
+	if token := os.Getenv("GOVITE_TOKEN"); token != "" {
+		router.Use(server.Guard(token, os.Getenv("GOVITE_HOST")))
+	}
+	server.Mount(router)
+
+	// The desktop shell passes a Unix socket, else listen on HOST:PORT
+	var listener net.Listener
+	var err error
+	if socket := os.Getenv("GOVITE_SOCKET"); socket != "" {
+		// A socket left behind by a crash would block the restart
+		os.Remove(socket)
+		listener, err = net.Listen("unix", socket)
+	} else {
+		host := os.Getenv("HOST")
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "8080"
+		}
+		listener, err = net.Listen("tcp", host+":"+port)
+	}
+	if err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+	log.Printf("Backend server listening on %s", listener.Addr())
+	// Tells the desktop shell the backend is ready
+	fmt.Println("GOVITE_READY")
+	if err := router.RunListener(listener); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
+`
+}
+
+func generateBackendServer() string {
+	return `// Package server sets up the backend's routes. cmd/server serves them as a
+// standalone process, and the desktop shell can mount them in-process.
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"backend/internal/api"
+	"github.com/gin-gonic/gin"
+)
+
+// Mount registers the backend's middleware and routes on router. It sends
+// no CORS headers: the desktop shell and the Vite dev server both proxy
+// /api, so the page never calls the backend cross-origin.
+func Mount(router *gin.Engine) {
+	api.SetupRoutes(router)
+}
+
+// Guard admits only the desktop shell when it runs the backend as a
+// sidecar. The shell addresses the backend as host and sends its launch
+// token as X-Govite-Token, so other local processes and DNS rebinding pages
+// are turned away. /health needs no token, the shell probes it at startup.
+func Guard(token, host string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Host != host {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unexpected Host header"})
+			return
+		}
+		if c.Request.URL.Path != "/health" && subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Govite-Token")), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid app token"})
+			return
+		}
+		c.Next()
+	}
+}
+`
+}
+
+func generateConfig() string {
+	return `package config
+
+import (
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	FrontendPort int
+	BackendPort  int
+	LogLevel     string
+}
+
+var globalConfig Config
+
+func LoadConfig() error {
+	frontendPort, _ := strconv.Atoi(getEnv("FRONTEND_PORT", "5173"))
+	backendPort, _ := strconv.Atoi(getEnv("BACKEND_PORT", "8080"))
+
+	globalConfig = Config{
+		FrontendPort: frontendPort,
+		BackendPort:  backendPort,
+		LogLevel:     getEnv("LOG_LEVEL", "info"),
+	}
+
+	return nil
+}
+
+func GetConfig() Config {
+	return globalConfig
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+`
+}
+
+func generateRoutes() string {
+	return `package api
+
+import (
+	"backend/internal/api/handlers"
+	"backend/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.Logger())
+	{
+		v1.GET("/items", handlers.ListItems)
+		v1.POST("/items", handlers.CreateItem)
+		v1.GET("/items/:id", handlers.GetItem)
+		v1.PUT("/items/:id", handlers.UpdateItem)
+		v1.DELETE("/items/:id", handlers.DeleteItem)
+	}
+}
+`
+}
+
+func generateHandlers() string {
+	return `package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func ListItems(c *gin.Context) {
+	c.JSON(200, gin.H{"items": []string{}})
+}
+
+func CreateItem(c *gin.Context) {
+	c.JSON(201, gin.H{"message": "Item created"})
+}
+
+func GetItem(c *gin.Context) {
+	id := c.Param("id")
+	c.JSON(200, gin.H{"id": id})
+}
+
+func UpdateItem(c *gin.Context) {
+	id := c.Param("id")
+	c.JSON(200, gin.H{"id": id, "message": "Item updated"})
+}
+
+func DeleteItem(c *gin.Context) {
+	id := c.Param("id")
+	c.JSON(200, gin.H{"id": id, "message": "Item deleted"})
+}
+`
+}
+
+func generateLoggerMiddleware() string {
+	return `package middleware
+
+import (
+	"log"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+)
+
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		
+		c.Next()
+		
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		
+		log.Printf("[%s] %s %d %v", c.Request.Method, path, status, latency)
+	}
+}
+`
+}
+
+func generatePipelineModel() string {
+	return `package models
+
+import "time"
+
+type Pipeline struct {
+	ID            string    ` + "`json:\"id\"`" + `
+	Name          string    ` + "`json:\"name\"`" + `
+	Status        string    ` + "`json:\"status\"`" + `
+	CurrentStep   int       ` + "`json:\"current_step\"`" + `
+	CompletedSteps []int    ` + "`json:\"completed_steps\"`" + `
+	CreatedAt     time.Time ` + "`json:\"created_at\"`" + `
+	UpdatedAt     time.Time ` + "`json:\"updated_at\"`" + `
+}
+`
+}
+
+func generateProjectModel() string {
+	return `package models
+
+import "time"
+
+type Project struct {
+	ID          string    ` + "`json:\"id\"`" + `
+	Name        string    ` + "`json:\"name\"`" + `
+	Description string    ` + "`json:\"description\"`" + `
+	Owner       string    ` + "`json:\"owner\"`" + `
+	CreatedAt   time.Time ` + "`json:\"created_at\"`" + `
+	UpdatedAt   time.Time ` + "`json:\"updated_at\"`" + `
+}
+`
+}
+
+func generateUserModel() string {
+	return `package models
+
+import "time"
+
+type User struct {
+	ID        string    ` + "`json:\"id\"`" + `
+	Email     string    ` + "`json:\"email\"`" + `
+	Name      string    ` + "`json:\"name\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+	UpdatedAt time.Time ` + "`json:\"updated_at\"`" + `
+}
+`
+}
+
+func generateModulesManager() string {
+	return `package modules
+
+type Module interface {
+	Name() string
+	Execute(input map[string]interface{}) (map[string]interface{}, error)
+	Validate(config map[string]interface{}) error
+}
+
+type Manager struct {
+	modules map[string]Module
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		modules: make(map[string]Module),
+	}
+}
+
+func (m *Manager) Register(name string, module Module) {
+	m.modules[name] = module
+}
+
+func (m *Manager) Get(name string) (Module, bool) {
+	module, ok := m.modules[name]
+	return module, ok
+}
+
+func (m *Manager) List() []string {
+	names := make([]string, 0, len(m.modules))
+	for name := range m.modules {
+		names = append(names, name)
+	}
+	return names
+}
+`
+}
+
+func generateBuiltinModules() string {
+	return `package modules
+
+type ExampleModule struct{}
+
+func (m *ExampleModule) Name() string {
+	return "example"
+}
+
+func (m *ExampleModule) Execute(input map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"status": "success",
+		"data":   input,
+	}, nil
+}
+
+func (m *ExampleModule) Validate(config map[string]interface{}) error {
+	return nil
+}
+
+func LoadBuiltinModules(manager *Manager) {
+	manager.Register("example", &ExampleModule{})
+}
+`
+}
+
+// NodeModuleRunner returns backend/internal/modules/node.go, which runs
+// Node.js backend modules. install-local restores it in projects that
+// predate it.
+func NodeModuleRunner() string {
+	return `package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// NodeModule runs a Node.js module that declares "govite": {"role": "backend"}
+// in its package.json. Each call starts the entry script with a JSON request
+// on stdin and expects a single JSON response on stdout:
+//
+//	request:  {"action": "execute" | "validate", "payload": {...}}
+//	response: {"result": {...}} or {"error": "message"}
+type NodeModule struct {
+	name  string
+	dir   string
+	entry string
+}
+
+// NewNodeModule creates a module backed by the script entry inside dir. A
+// relative dir is resolved against the project root.
+func NewNodeModule(name, dir, entry string) *NodeModule {
+	return &NodeModule{name: name, dir: dir, entry: entry}
+}
+
+func (m *NodeModule) Name() string {
+	return m.name
+}
+
+func (m *NodeModule) Execute(input map[string]interface{}) (map[string]interface{}, error) {
+	return m.call("execute", input)
+}
+
+func (m *NodeModule) Validate(config map[string]interface{}) error {
+	_, err := m.call("validate", config)
+	return err
+}
+
+func (m *NodeModule) call(action string, payload map[string]interface{}) (map[string]interface{}, error) {
+	request, err := json.Marshal(map[string]interface{}{
+		"action":  action,
+		"payload": payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("node", m.entry)
+	cmd.Dir = m.resolveDir()
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("node module %s failed: %v: %s", m.name, err, stderr.String())
+	}
+
+	var response struct {
+		Result map[string]interface{} ` + "`json:\"result\"`" + `
+		Error  string                 ` + "`json:\"error\"`" + `
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("node module %s returned invalid JSON: %v", m.name, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+	return response.Result, nil
+}
+
+// resolveDir finds the module directory whether the backend runs from the
+// project root or from the backend directory.
+func (m *NodeModule) resolveDir() string {
+	if filepath.IsAbs(m.dir) {
+		return m.dir
+	}
+	if root := os.Getenv("GOVITE_PROJECT_ROOT"); root != "" {
+		return filepath.Join(root, m.dir)
+	}
+	for _, root := range []string{".", ".."} {
+		candidate := filepath.Join(root, m.dir)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return m.dir
+}
+`
+}
+
+func generateDatabase() string {
+	return `package storage
+
+type Database struct {
+	// Add your database implementation here
+}
+
+func NewDatabase() (*Database, error) {
+	return &Database{}, nil
+}
+
+func (db *Database) Close() error {
+	return nil
+}
+`
+}
+
+func generateMigrations() string {
+	return `package storage
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is a SQL file installed by a module into migrations/<module>/.
+type Migration struct {
+	Module string
+	Name   string
+	SQL    string
+}
+
+// Migrations returns every installed migration ordered by module and file name.
+func Migrations() ([]Migration, error) {
+	var migrations []Migration
+	err := fs.WalkDir(migrationFiles, "migrations", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".sql") {
+			return err
+		}
+		content, err := migrationFiles.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		migrations = append(migrations, Migration{
+			Module: path.Base(path.Dir(p)),
+			Name:   path.Base(p),
+			SQL:    string(content),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].Module != migrations[j].Module {
+			return migrations[i].Module < migrations[j].Module
+		}
+		return migrations[i].Name < migrations[j].Name
+	})
+	return migrations, nil
+}
+
+// Migrate passes every installed migration to apply in order.
+func (db *Database) Migrate(apply func(Migration) error) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if err := apply(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`
+}
+
+func generateMigrationsReadme() string {
+	return `# Migrations
+
+Modules installed with go-vite copy their SQL migrations into a
+subdirectory named after the module. They are embedded into the backend
+and returned in order by storage.Migrations().
+`
+}
+
+func generateLogger() string {
+	return `package utils
+
+import (
+	"log"
+	"os"
+)
+
+var Logger = log.New(os.Stdout, "[APP] ", log.LstdFlags|log.Lshortfile)
+
+func Info(v ...interface{}) {
+	Logger.Println(v...)
+}
+
+func Error(v ...interface{}) {
+	Logger.Println(v...)
+}
+`
+}
+
+// Frontend generators
+func generatePackageJson(config Config) string {
+	return `{
+  "name": ` + jsonString(config.Name) + `,
+  "version": "1.0.0",
+  "description": ` + jsonString(config.Description) + `,
+  "type": "module",
+  "scripts": {
+    "dev": "vite",
+    "build": "vite build",
+    "preview": "vite preview",
+    "lint": "eslint . --ext js,jsx,ts,tsx",
+    "format": "prettier --write \"src/**/*.{js,jsx,ts,tsx,json,css,md}\""
+  },
+  "dependencies": {
+    "react": "^18.2.0",
+    "react-dom": "^18.2.0",
+    "lucide-react": "^0.294.0",
+    "axios": "^1.6.2"
+  },
+  "devDependencies": {
+    "@types/react": "^18.2.43",
+    "@types/react-dom": "^18.2.17",
+    "@vitejs/plugin-react": "^4.2.1",
+    "autoprefixer": "^10.4.16",
+    "eslint": "^8.55.0",
+    "eslint-plugin-react": "^7.33.2",
+    "eslint-plugin-react-hooks": "^4.6.0",
+    "eslint-plugin-react-refresh": "^0.4.5",
+    "postcss": "^8.4.32",
+    "prettier": "^3.1.1",
+    "tailwindcss": "^3.3.6",
+    "vite": "^5.0.8"
+  }
+}
+`
+}
+
+func generateViteConfig(config Config) string {
+	return `import { defineConfig } from 'vite'
+import react from '@vitejs/plugin-react'
+import path from 'path'
+
+export default defineConfig({
+  plugins: [react()],
+  resolve: {
+    alias: {
+      '@': path.resolve(__dirname, './src'),
+    },
+  },
+  server: {
+    port: ` + fmt.Sprintf("%d", config.Port) + `,
+    host: true,
+    proxy: {
+      '/api': {
+        target: 'http://localhost:` + fmt.Sprintf("%d", config.BackendPort) + `',
+        changeOrigin: true,
+        secure: false,
+      },
+    },
+  },
+  build: {
+    outDir: 'dist',
+    sourcemap: true,` + viteBridgeBuild(config) + `
+  },
+})
+`
+}
+
+// viteBridgeBuild returns the build options the bridge transport needs: it
+// loads index.html's stylesheet and script as text, so everything is bundled
+// into one of each with assets inlined.
+func viteBridgeBuild(config Config) string {
+	if config.Transport != TransportBridge {
+		return ""
+	}
+	return `
+    assetsInlineLimit: 100000000,
+    cssCodeSplit: false,
+    rollupOptions: {
+      output: { inlineDynamicImports: true },
+    },`
+}
+
+func generateTailwindConfig() string {
+	return `/** @type {import('tailwindcss').Config} */
+export default {
+  content: [
+    "./index.html",
+    "./src/**/*.{js,ts,jsx,tsx}",
+  ],
+  theme: {
+    extend: {
+      colors: {
+        brand: {
+          50: '#ecfeff',
+          100: '#cffafe',
+          200: '#a5f3fc',
+          300: '#67e8f9',
+          400: '#22d3ee',
+          500: '#06b6d4',
+          600: '#0891b2',
+          700: '#0e7490',
+          800: '#155e75',
+          900: '#164e63',
+        },
+      },
+    },
+  },
+  plugins: [],
+}
+`
+}
+
+func generatePostcssConfig() string {
+	return `export default {
+  plugins: {
+    tailwindcss: {},
+    autoprefixer: {},
+  },
+}
+`
+}
+
+func generateIndexHtml(config Config) string {
+	return `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <link rel="icon" type="image/svg+xml" href="/vite.svg" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <title>` + htmlText(config.Name) + `</title>
+  </head>
+  <body>
+    <div id="root"></div>
+    <script type="module" src="/src/main.tsx"></script>
+  </body>
+</html>
+`
+}
+
+func generateMainTsx() string {
+	return `import React from 'react'
+import ReactDOM from 'react-dom/client'
+import App from './App.tsx'
+import './index.css'
+
+ReactDOM.createRoot(document.getElementById('root')!).render(
+  <React.StrictMode>
+    <App />
+  </React.StrictMode>,
+)
+`
+}
+
+func generateAppTsx(config Config) string {
+	return `import React, { useState, useEffect } from 'react';
+import { Play, Settings } from 'lucide-react';
+
+function App() {
+  const [status, setStatus] = useState<string>('idle');
+  const [items, setItems] = useState<any[]>([]);
+
+  useEffect(() => {
+    fetchItems();
+  }, []);
+
+  const fetchItems = async () => {
+    try {
+      const response = await fetch('/api/v1/items');
+      const data = await response.json();
+      setItems(data.items || []);
+    } catch (error) {
+      console.error('Failed to fetch items:', error);
+    }
+  };
+
+  return (
+    <div className="min-h-screen bg-gradient-to-br from-slate-900 via-brand-900 to-slate-900 text-white p-6">
+      <div className="max-w-7xl mx-auto">
+        <div className="text-center mb-8">
+          <h1 className="text-5xl font-bold mb-2 bg-gradient-to-r from-brand-400 to-blue-400 bg-clip-text text-transparent">
+            ` + tsxString(config.Name) + `
+          </h1>
+          <p className="text-lg text-gray-300">` + tsxString(config.Description) + `</p>
+        </div>
+
+        <div className="bg-slate-800/50 backdrop-blur-sm rounded-xl p-6 mb-8 border border-brand-500/30">
+          <div className="flex items-center justify-between mb-4">
+            <h2 className="text-2xl font-bold">Dashboard</h2>
+            <span className="px-3 py-1 bg-green-600/30 text-green-300 rounded-full text-sm">
+              {status}
+            </span>
+          </div>
+          
+          <div className="flex gap-3">
+            <button
+              onClick={() => setStatus('running')}
+              className="flex items-center gap-2 bg-gradient-to-r from-brand-600 to-blue-600 px-6 py-3 rounded-lg font-semibold hover:from-brand-500 hover:to-blue-500 transition-all"
+            >
+              <Play className="w-5 h-5" />
+              Start
+            </button>
+            <button className="flex items-center gap-2 bg-slate-700 px-6 py-3 rounded-lg font-semibold hover:bg-slate-600 transition-all">
+              <Settings className="w-5 h-5" />
+              Settings
+            </button>
+          </div>
+        </div>
+
+        <div className="bg-slate-800/50 backdrop-blur-sm rounded-xl p-6 border border-brand-500/30">
+          <h3 className="text-xl font-bold mb-4">Items</h3>
+          {items.length === 0 ? (
+            <p className="text-gray-400">No items yet</p>
+          ) : (
+            <ul className="space-y-2">
+              {items.map((item, idx) => (
+                <li key={idx} className="p-3 bg-slate-700/50 rounded-lg">
+                  {JSON.stringify(item)}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
+
+export default App;
+`
+}
+
+func generateIndexCss() string {
+	return `@tailwind base;
+@tailwind components;
+@tailwind utilities;
+`
+}
+
+func generateEslintrc() string {
+	return `module.exports = {
+  root: true,
+  env: { browser: true, es2020: true },
+  extends: [
+    'eslint:recommended',
+    'plugin:react/recommended',
+    'plugin:react/jsx-runtime',
+    'plugin:react-hooks/recommended',
+  ],
+  ignorePatterns: ['dist', '.eslintrc.cjs'],
+  parserOptions: { ecmaVersion: 'latest', sourceType: 'module' },
+  settings: { react: { version: '18.2' } },
+  plugins: ['react-refresh'],
+  rules: {
+    'react-refresh/only-export-components': [
+      'warn',
+      { allowConstantExport: true },
+    ],
+    'react/prop-types': 'off',
+  },
+}
+`
+}
+
+func generatePrettierrc() string {
+	return `{
+  "semi": true,
+  "trailingComma": "es5",
+  "singleQuote": true,
+  "printWidth": 100,
+  "tabWidth": 2,
+  "useTabs": false
+}
+`
+}
@@ -0,0 +1,1206 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoMod(t *testing.T) {
+	config := Config{
+		Module: "github.com/test/my-app",
+	}
+
+	result := generateGoMod(config)
+	expected := "module github.com/test/my-app"
+	if !strings.Contains(result, expected) {
+		t.Fatalf("Expected %s in result, got: %s", expected, result)
+	}
+
+	if !strings.Contains(result, "go 1.24.0") {
+		t.Fatal("Expected Go version in go.mod")
+	}
+}
+
+func TestGenerateMainGo(t *testing.T) {
+	result := generateMainGo()
+	if !strings.Contains(result, "package main") {
+		t.Fatal("Expected package main")
+	}
+
+	if !strings.Contains(result, "webview") {
+		t.Fatal("Expected webview import")
+	}
+
+	if !strings.Contains(result, "func main()") {
+		t.Fatal("Expected main function")
+	}
+
+	if !strings.Contains(result, `net.Listen("tcp", addr)`) || !strings.Contains(result, `"127.0.0.1:0"`) {
+		t.Fatal("Expected the shell to listen on a free loopback port")
+	}
+
+	if !strings.Contains(result, "w.Navigate(app.url)") {
+		t.Fatal("Expected the webview to open the allocated address")
+	}
+
+	if strings.Contains(result, "Access-Control-Allow-Origin") || !strings.Contains(result, "app.router.Use(app.guardRequests())") {
+		t.Fatal("Expected requests to be guarded instead of allowing any origin")
+	}
+
+	if !strings.Contains(result, "w.Init(tokenScript(app.token, app.guard.origin))") {
+		t.Fatal("Expected the launch token to be injected into the webview")
+	}
+}
+
+func TestGenerateMakefile(t *testing.T) {
+	config := Config{
+		Name: "test-app",
+	}
+
+	result := generateMakefile(config)
+	if !strings.Contains(result, "PROJECT_NAME := test-app") {
+		t.Fatal("Expected project name in Makefile")
+	}
+
+	if !strings.Contains(result, ".PHONY: help all") {
+		t.Fatal("Expected make targets")
+	}
+}
+
+func TestGenerateReadme(t *testing.T) {
+	config := Config{
+		Name:        "Test App",
+		Description: "A test application",
+	}
+
+	result := generateReadme(config)
+	if !strings.Contains(result, "# Test App") {
+		t.Fatal("Expected title in README")
+	}
+
+	if !strings.Contains(result, "A test application") {
+		t.Fatal("Expected description in README")
+	}
+}
+
+func TestGenerateBackendGoMod(t *testing.T) {
+	result := generateBackendGoMod()
+	expected := "module backend"
+	if !strings.Contains(result, expected) {
+		t.Fatalf("Expected %s in result, got: %s", expected, result)
+	}
+
+	if !strings.Contains(result, "go 1.24") {
+		t.Fatal("Expected Go version")
+	}
+}
+
+func TestGenerateBackendMain(t *testing.T) {
+	result := generateBackendMain()
+	if !strings.Contains(result, "package main") {
+		t.Fatal("Expected package main")
+	}
+
+	if !strings.Contains(result, "gin.Default()") {
+		t.Fatal("Expected Gin router")
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	result := generateConfig()
+	if !strings.Contains(result, "package config") {
+		t.Fatal("Expected package config")
+	}
+
+	if !strings.Contains(result, "type Config struct") {
+		t.Fatal("Expected Config struct")
+	}
+}
+
+func TestGenerateRoutes(t *testing.T) {
+	result := generateRoutes()
+	if !strings.Contains(result, "package api") {
+		t.Fatal("Expected package api")
+	}
+
+	if !strings.Contains(result, "SetupRoutes") {
+		t.Fatal("Expected SetupRoutes function")
+	}
+
+	if strings.Contains(result+generateBackendServer(), "Access-Control-Allow-Origin") {
+		t.Fatal("Expected the backend to send no CORS headers")
+	}
+}
+
+func TestGenerateBackendGuard(t *testing.T) {
+	if !strings.Contains(generateBackendMain(), `router.Use(server.Guard(token, os.Getenv("GOVITE_HOST")))`) {
+		t.Fatal("Expected the backend to be guarded when the shell passes a token")
+	}
+
+	shell := generateBackendSidecarGo()
+	if !strings.Contains(shell, `"GOVITE_TOKEN="+app.token, "GOVITE_HOST="+target.Host`) {
+		t.Fatal("Expected the shell to pass the token and host to the sidecar")
+	}
+
+	server := generateBackendServer()
+	for _, check := range []string{"c.Request.Host != host", `c.GetHeader("X-Govite-Token")`} {
+		if !strings.Contains(server, check) {
+			t.Fatalf("Expected %s in the backend guard", check)
+		}
+	}
+}
+
+func TestGenerateHandlers(t *testing.T) {
+	result := generateHandlers()
+	if !strings.Contains(result, "package handlers") {
+		t.Fatal("Expected package handlers")
+	}
+
+	if !strings.Contains(result, "ListItems") {
+		t.Fatal("Expected ListItems function")
+	}
+}
+
+func TestGenerateLoggerMiddleware(t *testing.T) {
+	result := generateLoggerMiddleware()
+	if !strings.Contains(result, "package middleware") {
+		t.Fatal("Expected package middleware")
+	}
+
+	if !strings.Contains(result, "Logger()") {
+		t.Fatal("Expected Logger function")
+	}
+}
+
+func TestGeneratePipelineModel(t *testing.T) {
+	result := generatePipelineModel()
+	if !strings.Contains(result, "package models") {
+		t.Fatal("Expected package models")
+	}
+
+	if !strings.Contains(result, "type Pipeline struct") {
+		t.Fatal("Expected Pipeline struct")
+	}
+}
+
+func TestGenerateProjectModel(t *testing.T) {
+	result := generateProjectModel()
+	if !strings.Contains(result, "package models") {
+		t.Fatal("Expected package models")
+	}
+
+	if !strings.Contains(result, "type Project struct") {
+		t.Fatal("Expected Project struct")
+	}
+}
+
+func TestGenerateUserModel(t *testing.T) {
+	result := generateUserModel()
+	if !strings.Contains(result, "package models") {
+		t.Fatal("Expected package models")
+	}
+
+	if !strings.Contains(result, "type User struct") {
+		t.Fatal("Expected User struct")
+	}
+}
+
+func TestGenerateModulesManager(t *testing.T) {
+	result := generateModulesManager()
+	if !strings.Contains(result, "package modules") {
+		t.Fatal("Expected package modules")
+	}
+
+	if !strings.Contains(result, "type Manager struct") {
+		t.Fatal("Expected Manager struct")
+	}
+}
+
+func TestGenerateBuiltinModules(t *testing.T) {
+	result := generateBuiltinModules()
+	if !strings.Contains(result, "package modules") {
+		t.Fatal("Expected package modules")
+	}
+
+	if !strings.Contains(result, "ExampleModule") {
+		t.Fatal("Expected ExampleModule")
+	}
+}
+
+func TestGenerateNodeModuleRunner(t *testing.T) {
+	result := NodeModuleRunner()
+	if !strings.Contains(result, "package modules") {
+		t.Fatal("Expected package modules")
+	}
+
+	if !strings.Contains(result, "func NewNodeModule(") {
+		t.Fatal("Expected NewNodeModule constructor")
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "node.go", result, 0); err != nil {
+		t.Fatalf("Generated node.go does not parse: %v", err)
+	}
+}
+
+func TestGenerateDatabase(t *testing.T) {
+	result := generateDatabase()
+	if !strings.Contains(result, "package storage") {
+		t.Fatal("Expected package storage")
+	}
+
+	if !strings.Contains(result, "type Database struct") {
+		t.Fatal("Expected Database struct")
+	}
+}
+
+func TestGenerateLogger(t *testing.T) {
+	result := generateLogger()
+	if !strings.Contains(result, "package utils") {
+		t.Fatal("Expected package utils")
+	}
+
+	if !strings.Contains(result, "Logger = log.New") {
+		t.Fatal("Expected Logger initialization")
+	}
+}
+
+func TestGeneratePackageJson(t *testing.T) {
+	config := Config{
+		Name:        "test-app",
+		Description: "Test app",
+	}
+
+	result := generatePackageJson(config)
+	if !strings.Contains(result, `"name": "test-app"`) {
+		t.Fatal("Expected package name")
+	}
+
+	if !strings.Contains(result, `"description": "Test app"`) {
+		t.Fatal("Expected description")
+	}
+}
+
+func TestGenerateViteConfig(t *testing.T) {
+	config := Config{
+		Port:        3000,
+		BackendPort: 9000,
+	}
+
+	result := generateViteConfig(config)
+	if !strings.Contains(result, "port: 3000") {
+		t.Fatal("Expected frontend port")
+	}
+
+	if !strings.Contains(result, "localhost:9000") {
+		t.Fatal("Expected backend port in proxy")
+	}
+}
+
+func TestGenerateTailwindConfig(t *testing.T) {
+	result := generateTailwindConfig()
+	if !strings.Contains(result, "brand:") {
+		t.Fatal("Expected brand colors")
+	}
+}
+
+func TestGeneratePostcssConfig(t *testing.T) {
+	result := generatePostcssConfig()
+	if !strings.Contains(result, "tailwindcss") {
+		t.Fatal("Expected tailwindcss plugin")
+	}
+}
+
+func TestGenerateIndexHtml(t *testing.T) {
+	config := Config{
+		Name: "Test App",
+	}
+
+	result := generateIndexHtml(config)
+	if !strings.Contains(result, "<title>Test App</title>") {
+		t.Fatal("Expected title")
+	}
+}
+
+func TestGenerateMainTsx(t *testing.T) {
+	result := generateMainTsx()
+	if !strings.Contains(result, "ReactDOM.createRoot") {
+		t.Fatal("Expected React root creation")
+	}
+}
+
+func TestGenerateAppTsx(t *testing.T) {
+	config := Config{
+		Name:        "Test App",
+		Description: "Test description",
+	}
+
+	result := generateAppTsx(config)
+	if !strings.Contains(result, "Test App") {
+		t.Fatal("Expected app name")
+	}
+
+	if !strings.Contains(result, "Test description") {
+		t.Fatal("Expected description")
+	}
+}
+
+func TestGenerateIndexCss(t *testing.T) {
+	result := generateIndexCss()
+	if !strings.Contains(result, "@tailwind") {
+		t.Fatal("Expected Tailwind directives")
+	}
+}
+
+func TestGenerateEslintrc(t *testing.T) {
+	result := generateEslintrc()
+	if !strings.Contains(result, "eslint:recommended") {
+		t.Fatal("Expected ESLint config")
+	}
+}
+
+func TestGeneratePrettierrc(t *testing.T) {
+	result := generatePrettierrc()
+	if !strings.Contains(result, `"semi": true`) {
+		t.Fatal("Expected Prettier config")
+	}
+}
+
+func TestGenerateGitignore(t *testing.T) {
+	result := generateGitignore()
+	if !strings.Contains(result, "node_modules/") {
+		t.Fatal("Expected node_modules in gitignore")
+	}
+}
+
+func TestGenerateGitattributes(t *testing.T) {
+	result := generateGitattributes()
+	if !strings.Contains(result, "* text=auto") {
+		t.Fatal("Expected gitattributes content")
+	}
+}
+
+func TestGenerateEnvExample(t *testing.T) {
+	config := Config{
+		Name:        "test-app",
+		BackendPort: 9000,
+	}
+
+	result := generateEnvExample(config)
+	if !strings.Contains(result, "VITE_APP_NAME=test-app") {
+		t.Fatal("Expected app name in env")
+	}
+
+	if !strings.Contains(result, "PORT=9000") {
+		t.Fatal("Expected backend port in env")
+	}
+}
+
+func TestGenerateNetlifyToml(t *testing.T) {
+	config := Config{
+		Name: "test-app",
+	}
+	result := generateNetlifyToml(config)
+	if !strings.Contains(result, "[build]") {
+		t.Fatal("Expected build section")
+	}
+}
+
+func TestGenerateNetlifyApiFunction(t *testing.T) {
+	result := generateNetlifyApiFunction()
+	if !strings.Contains(result, "exports.handler") {
+		t.Fatal("Expected Netlify function")
+	}
+}
+
+func TestGenerateMigrations(t *testing.T) {
+	result := generateMigrations()
+	if !strings.Contains(result, "//go:embed migrations") {
+		t.Fatal("Expected embedded migrations directory")
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "migrations.go", result, 0); err != nil {
+		t.Fatalf("Generated migrations.go does not parse: %v", err)
+	}
+}
+
+func TestGenerateBindingsGo(t *testing.T) {
+	result := generateBindingsGo()
+	if !strings.Contains(result, "type Service struct") {
+		t.Fatal("Expected Service struct")
+	}
+
+	if !strings.Contains(result, `w.Bind("__go_"+name, call)`) {
+		t.Fatal("Expected methods to be bound into the webview")
+	}
+
+	if !strings.Contains(result, "app.policy.AllowBinding(origin, name)") {
+		t.Fatal("Expected binding calls to be checked against the policy")
+	}
+
+	if !strings.Contains(result, "origin := app.guard.callerOrigin(secret)") || strings.Contains(result, "func(origin string, args") {
+		t.Fatal("Expected Go to decide the caller's origin rather than the page")
+	}
+
+	if !strings.Contains(result, `delete window["__go_" + name];`) {
+		t.Fatal("Expected the shim to hide the raw bindings from pages")
+	}
+
+	if !strings.Contains(generateMainGo(), "bindService(w, app, &Service{app: app})") {
+		t.Fatal("Expected main.go to bind the service")
+	}
+}
+
+func TestGenerateBindingsTs(t *testing.T) {
+	result := generateBindingsTs()
+	if !strings.Contains(result, "export function Greet(name: string): Promise<string>") {
+		t.Fatalf("Expected a typed Greet wrapper, got:\n%s", result)
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	result := generateManifest(Config{Name: `my "app"`})
+	if !strings.Contains(result, `"name": "my \"app\""`) {
+		t.Fatalf("Expected the escaped name, got:\n%s", result)
+	}
+
+	if !strings.Contains(result, `"origins": ["app"]`) {
+		t.Fatal("Expected the app origin to be granted")
+	}
+
+	if !strings.Contains(result, `{ "route": "/api/**", "timeout": "30s" }`) {
+		t.Fatal("Expected the default route timeout")
+	}
+}
+
+// testGeneratedFiles runs go test on generated shell files that only need
+// the standard library, together with a test file for them.
+func testGeneratedFiles(t *testing.T, files map[string]string) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+
+	dir := t.TempDir()
+	files["go.mod"] = "module app\n\ngo 1.22\n"
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated files failed their tests: %v\n%s", err, output)
+	}
+}
+
+func TestGeneratedCapabilities(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"capabilities.go": generateCapabilitiesGo(),
+		"capabilities_test.go": `package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPolicy(t *testing.T) {
+	scope := t.TempDir()
+	os.WriteFile(filepath.Join(scope, "notes.txt"), nil, 0644)
+	os.Symlink("/", filepath.Join(scope, "root"))
+
+	manifest, err := LoadManifest([]byte(` + "`" + `{
+		"name": "demo",
+		"capabilities": [
+			{"origins": ["app"], "bindings": ["Info", "Read*"], "fs": [{"path": "` + "` + scope + `" + `", "access": "read"}], "routes": ["/api/**"]},
+			{"origins": ["https://docs.example.com"], "routes": ["GET /api/v1/docs/*"]}
+		]
+	}` + "`" + `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPolicy(manifest, "http://localhost:5173")
+	app, docs, evil := "http://localhost:5173", "https://docs.example.com", "https://evil.example.com"
+
+	checks := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"app binding", p.AllowBinding(app, "Info"), true},
+		{"app binding wildcard", p.AllowBinding(app, "ReadTextFile"), true},
+		{"ungranted binding", p.AllowBinding(app, "Delete"), false},
+		{"foreign binding", p.AllowBinding(evil, "Info"), false},
+		{"app route", p.AllowRoute(app, "POST", "/api/v1/items"), true},
+		{"docs route", p.AllowRoute(docs, "GET", "/api/v1/docs/intro"), true},
+		{"docs route method", p.AllowRoute(docs, "POST", "/api/v1/docs/intro"), false},
+		{"docs route depth", p.AllowRoute(docs, "GET", "/api/v1/docs/a/b"), false},
+		{"no origin route", p.AllowRoute("", "GET", "/api/v1/items"), false},
+		{"no origin before start", NewPolicy(manifest, "").AllowRoute("", "GET", "/api/v1/items"), false},
+		{"opaque app origin", NewPolicy(manifest, "null").AllowRoute("null", "GET", "/api/v1/items"), true},
+		{"read in scope", p.AllowPath(app, filepath.Join(scope, "notes.txt"), false), true},
+		{"write read-only scope", p.AllowPath(app, filepath.Join(scope, "notes.txt"), true), false},
+		{"escape scope", p.AllowPath(app, filepath.Join(scope, "..", "other"), false), false},
+		{"escape through symlink", p.AllowPath(app, filepath.Join(scope, "root", "etc"), false), false},
+		{"foreign path", p.AllowPath(evil, filepath.Join(scope, "notes.txt"), false), false},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	for _, invalid := range []string{
+		` + "`" + `{"capabilities": [{"bindings": ["*"]}]}` + "`" + `,
+		` + "`" + `{"capabilities": [{"origins": ["app"], "fs": [{"path": "/", "access": "all"}]}]}` + "`" + `,
+		` + "`" + `{"capabilities": [{"origins": ["app"], "routes": ["api/**"]}]}` + "`" + `,
+		` + "`" + `{"transport": "pigeon", "capabilities": []}` + "`" + `,
+	} {
+		if _, err := LoadManifest([]byte(invalid)); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestRouteContext(t *testing.T) {
+	manifest, err := LoadManifest([]byte(` + "`" + `{"timeouts": [{"route": "/api/slow", "timeout": "0"}]}` + "`" + `))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := manifest.routeContext(httptest.NewRequest("GET", "/api/users", nil))
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > defaultRouteTimeout {
+		t.Errorf("Expected the default timeout, got %v, %v", deadline, ok)
+	}
+	ctx, cancel = manifest.routeContext(httptest.NewRequest("GET", "/api/slow", nil))
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no limit for a 0 timeout")
+	}
+	ws := httptest.NewRequest("GET", "/api/ws", nil)
+	ws.Header.Set("Connection", "keep-alive, Upgrade")
+	ws.Header.Set("Upgrade", "websocket")
+	ctx, cancel = manifest.routeContext(ws)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected upgraded connections not to be limited")
+	}
+}
+`,
+	})
+}
+
+func TestGeneratedBridge(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"bridge.go": generateBridgeGo(),
+		"guard.go":  generateGuardGo(),
+		"bridge_test.go": `package main
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServeBridge(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Origin", r.Header.Get("Origin"))
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+	})
+
+	resp, err := serveBridge(handler, "null", bridgeRequest{
+		Method:  "POST",
+		URL:     "/api/v1/items?sort=name",
+		Headers: map[string]string{"content-type": "application/json", "origin": "https://evil.example.com"},
+		Body:    base64.StdEncoding.EncodeToString([]byte("{}")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := base64.StdEncoding.DecodeString(resp.Body)
+	if resp.Status != 201 || string(body) != "POST /api/v1/items?sort=name application/json {}" {
+		t.Fatalf("Unexpected response %d %q", resp.Status, body)
+	}
+	if resp.Headers["X-Origin"] != "null" || resp.Headers["Set-Cookie"] != "a=1, b=2" {
+		t.Fatalf("Unexpected headers %v", resp.Headers)
+	}
+
+	empty, err := serveBridge(http.NotFoundHandler(), "null", bridgeRequest{Method: "GET", URL: "/missing"})
+	if err != nil || empty.Status != 404 {
+		t.Fatalf("Expected 404, got %+v %v", empty, err)
+	}
+
+	for _, url := range []string{"https://example.com/", "//example.com/"} {
+		if _, err := serveBridge(handler, "null", bridgeRequest{Method: "GET", URL: url}); err == nil {
+			t.Errorf("Expected %s to be refused", url)
+		}
+	}
+}
+
+func TestBridgeCall(t *testing.T) {
+	guard := &requestGuard{token: "secret", host: "bridge", origin: "null"}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status, err := guard.check(r, true); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		io.WriteString(w, r.Header.Get("Origin"))
+	})
+
+	// The page can neither name an origin nor supply its own token
+	forged := bridgeRequest{Method: "GET", URL: "/api/v1/items", Headers: map[string]string{"Origin": "null", "x-govite-token": "guess"}}
+	for _, secret := range []string{"", "null", "guess"} {
+		if _, err := bridgeCall(handler, guard, secret, forged); err == nil {
+			t.Errorf("Expected a call with %q to be refused", secret)
+		}
+	}
+
+	resp, err := bridgeCall(handler, guard, "secret", forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := base64.StdEncoding.DecodeString(resp.Body)
+	if resp.Status != 200 || string(body) != "null" {
+		t.Fatalf("Expected the app's request to pass the guard, got %d %q", resp.Status, body)
+	}
+
+	script := bridgeScript("null", "secret")
+	if !strings.Contains(script, "delete window.__govite_fetch;") || !strings.Contains(script, ` + "`" + `const secret = "secret";` + "`" + `) {
+		t.Fatalf("Unexpected bridge script:\n%s", script)
+	}
+}
+`,
+	})
+}
+
+func TestGenerateBridgeBuild(t *testing.T) {
+	config := Config{Port: 5173, BackendPort: 8080, Transport: TransportBridge}
+	if !strings.Contains(generateViteConfig(config), "inlineDynamicImports: true") {
+		t.Fatal("Expected the bridge build to bundle into one script")
+	}
+	if !strings.Contains(generateManifest(config), `"transport": "bridge"`) {
+		t.Fatal("Expected the transport in govite.json")
+	}
+	// about:blank cannot load app paths, so the page's assets go through the bridge
+	for _, want := range []string{
+		"govite.assetURL = (url) =>",
+		"URL.createObjectURL(await res.blob())",
+		`link[rel~="icon"][href]`,
+		"style.textContent = await withAssetURLs(style.textContent);",
+	} {
+		if !strings.Contains(generateBridgeGo(), want) {
+			t.Fatalf("Expected the bridge shim to contain %s", want)
+		}
+	}
+
+	config.Transport = ""
+	if strings.Contains(generateViteConfig(config), "inlineDynamicImports") {
+		t.Fatal("Expected the default build to keep code splitting")
+	}
+	if !strings.Contains(generateManifest(config), `"transport": "http"`) {
+		t.Fatal("Expected the http transport by default")
+	}
+}
+
+func TestGeneratedStartup(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"startup.go": generateStartupGo(),
+		"startup_test.go": `package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitReady(t *testing.T) {
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probes++; probes < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if err := waitReady(server.URL, nil, nil, nil, 5*time.Second); err != nil {
+		t.Fatalf("Expected the backend to become healthy, got %v", err)
+	}
+	if probes != 3 {
+		t.Fatalf("Expected 3 probes, got %d", probes)
+	}
+
+	ready := make(chan struct{})
+	close(ready)
+	if err := waitReady("http://127.0.0.1:1/health", nil, ready, nil, 5*time.Second); err != nil {
+		t.Fatalf("Expected the readiness line to count, got %v", err)
+	}
+
+	exited := make(chan struct{})
+	close(exited)
+	if err := waitReady("http://127.0.0.1:1/health", nil, nil, exited, 5*time.Second); !errors.Is(err, errBackendExited) {
+		t.Fatalf("Expected an early exit to fail, got %v", err)
+	}
+
+	if err := waitReady("http://127.0.0.1:1/health", nil, nil, nil, 50*time.Millisecond); err == nil {
+		t.Fatal("Expected the deadline to fail")
+	}
+}
+
+func TestReadyWriter(t *testing.T) {
+	var out strings.Builder
+	w := newReadyWriter(&out)
+	io.WriteString(w, "listening\nGOVITE_")
+	select {
+	case <-w.ready:
+		t.Fatal("Expected no readiness before the full line")
+	default:
+	}
+	io.WriteString(w, "READY\n")
+	io.WriteString(w, "GOVITE_READY\n")
+	select {
+	case <-w.ready:
+	default:
+		t.Fatal("Expected the readiness line to close ready")
+	}
+	if out.String() != "listening\nGOVITE_READY\nGOVITE_READY\n" {
+		t.Fatalf("Expected the output to pass through, got %q", out.String())
+	}
+}
+
+func TestStartupErrorPage(t *testing.T) {
+	page := startupErrorPage(errors.New("backend exited during startup: <exit status 1>"))
+	if !strings.Contains(page, "&lt;exit status 1&gt;") {
+		t.Fatalf("Expected the error to be escaped, got:\n%s", page)
+	}
+}
+`,
+	})
+}
+
+func TestGeneratedSupervisor(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"startup.go":    generateStartupGo(),
+		"supervisor.go": generateSupervisorGo(),
+		"supervisor_test.go": `package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testSupervisor(script string) *supervisor {
+	return &supervisor{
+		command:      func() *exec.Cmd { return exec.Command("sh", "-c", script) },
+		healthURL:    "http://127.0.0.1:1/health",
+		readyTimeout: 5 * time.Second,
+		output:       io.Discard,
+		minBackoff:   time.Millisecond,
+		maxBackoff:   10 * time.Millisecond,
+		maxRestarts:  2,
+		crashWindow:  time.Minute,
+	}
+}
+
+func TestSupervisorRestarts(t *testing.T) {
+	s := testSupervisor("echo GOVITE_READY; exit 3")
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-s.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the supervisor to give up on a crash loop")
+	}
+	status := s.Status()
+	if status.State != "failed" || status.Restarts != 2 || status.LastExitCode == nil || *status.LastExitCode != 3 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+}
+
+func TestSupervisorStop(t *testing.T) {
+	s := testSupervisor("echo GOVITE_READY; exec sleep 30")
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if status := s.Status(); status.State != "running" || status.PID == 0 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+	s.Stop()
+	if status := s.Status(); status.State != "stopped" || status.PID != 0 || status.Restarts != 0 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+}
+
+func TestSupervisorStartFailure(t *testing.T) {
+	s := testSupervisor("exit 1")
+	if err := s.Start(); !errors.Is(err, errBackendExited) {
+		t.Fatalf("Expected an early exit to fail the start, got %v", err)
+	}
+	if status := s.Status(); status.State != "failed" {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+	s.Stop()
+}
+
+func TestSupervisorStopAfterFailedRestart(t *testing.T) {
+	s := testSupervisor("")
+	missing := filepath.Join(t.TempDir(), "missing")
+	launches := 0
+	s.command = func() *exec.Cmd {
+		launches++
+		if launches == 1 {
+			return exec.Command("sh", "-c", "echo GOVITE_READY; exit 3")
+		}
+		// Every restart fails before there is a process
+		return exec.Command(missing)
+	}
+	s.maxRestarts = 1000
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(10 * time.Second); s.Status().Restarts < 3; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected restarts, got %+v", s.Status())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+	if status := s.Status(); status.State != "stopped" || status.LastExitCode == nil || *status.LastExitCode != -1 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+}
+
+func TestRotatingLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "backend.log")
+	l, err := openRotatingLog(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		if _, err := io.WriteString(l, line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	want := map[string]string{path: "four\nfive\n", path + ".1": "three\n", path + ".2": "one\ntwo\n"}
+	for file, content := range want {
+		got, err := os.ReadFile(file)
+		if err != nil || string(got) != content {
+			t.Errorf("Expected %s to hold %q, got %q (%v)", file, content, got, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("Expected at most 2 rotated files")
+	}
+	if info, _ := os.Stat(filepath.Dir(path)); info.Mode().Perm() != 0700 {
+		t.Errorf("Expected a private log dir, got %v", info.Mode().Perm())
+	}
+}
+`,
+	})
+}
+
+func TestGeneratedProxy(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"capabilities.go": generateCapabilitiesGo(),
+		"guard.go":        generateGuardGo(),
+		"proxy.go":        generateProxyGo(),
+		"proxy_test.go": `package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackendProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/echo":
+			fmt.Fprintf(w, "%s|%s|%s|%s|%s", r.URL.RequestURI(), r.Header.Get("X-Forwarded-Host"), r.Header.Get("Keep-Alive"), r.Header.Get("X-Custom"), r.Header.Get(tokenHeader))
+		case "/api/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/api/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: first\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/api/ws":
+			conn, rw, _ := w.(http.Hijacker).Hijack()
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+			rw.Flush()
+			line, _ := rw.ReadString('\n')
+			rw.WriteString("echo " + line)
+			rw.Flush()
+		}
+	}))
+	defer backend.Close()
+
+	manifest, err := LoadManifest([]byte(` + "`" + `{"timeouts": [{"route": "/api/slow", "timeout": "50ms"}, {"route": "GET /api/events", "timeout": "0"}]}` + "`" + `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, _ := url.Parse(backend.URL)
+	shell := httptest.NewServer(newBackendProxy(target, newBackendTransport(""), "secret", manifest))
+	defer shell.Close()
+
+	req, _ := http.NewRequest("GET", shell.URL+"/api/echo?q=go+vite&govite_token=leaked&page=2&govite%5Ftoken=leaked", nil)
+	req.Header.Set("Connection", "Keep-Alive")
+	req.Header.Set("Keep-Alive", "timeout=5")
+	req.Header.Set("X-Custom", "kept")
+	req.Header.Set(tokenHeader, "forged")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if want := "/api/echo?q=go+vite&page=2|" + strings.TrimPrefix(shell.URL, "http://") + "||kept|secret"; string(body) != want {
+		t.Fatalf("Expected %q, got %q", want, body)
+	}
+
+	resp, err = http.Get(shell.URL + "/api/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("Expected the route timeout to answer 504, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(shell.URL + "/api/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := bufio.NewReader(resp.Body).ReadString('\n')
+	resp.Body.Close()
+	if err != nil || event != "data: first\n" {
+		t.Fatalf("Expected the event to be streamed, got %q %v", event, err)
+	}
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(shell.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "GET /api/ws HTTP/1.1\r\nHost: app\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	upgraded, err := http.ReadResponse(reader, nil)
+	if err != nil || upgraded.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected the upgrade to pass through, got %v %v", upgraded, err)
+	}
+	fmt.Fprint(conn, "hello\n")
+	if line, _ := reader.ReadString('\n'); line != "echo hello\n" {
+		t.Fatalf("Expected the upgraded connection to be relayed, got %q", line)
+	}
+
+	backend.Close()
+	resp, err = http.Get(shell.URL + "/api/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected 502 without a backend, got %d", resp.StatusCode)
+	}
+}
+
+func TestSocketTransport(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	dir, socket, err := newSocketDir()
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Fatalf("Expected a private socket dir, got %v %v", info, err)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Host, r.URL.RequestURI())
+	})}
+	go backend.Serve(listener)
+	defer backend.Close()
+
+	manifest, _ := LoadManifest([]byte(` + "`" + `{}` + "`" + `))
+	target := &url.URL{Scheme: "http", Host: "backend"}
+	shell := httptest.NewServer(newBackendProxy(target, newBackendTransport(socket), "secret", manifest))
+	defer shell.Close()
+
+	resp, err := http.Get(shell.URL + "/api/items?govite%5Ftoken=leaked&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "backend /api/items?page=2" {
+		t.Fatalf("Expected the request to reach the socket, got %q", body)
+	}
+}
+
+func TestRouteTimeout(t *testing.T) {
+	manifest, err := LoadManifest([]byte(` + "`" + `{"timeouts": [{"route": "POST /api/upload", "timeout": "5m"}]}` + "`" + `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.RouteTimeout("POST", "/api/upload"); got != 5*time.Minute {
+		t.Errorf("Expected 5m, got %s", got)
+	}
+	if got := manifest.RouteTimeout("GET", "/api/upload"); got != defaultRouteTimeout {
+		t.Errorf("Expected the default timeout, got %s", got)
+	}
+	if _, err := LoadManifest([]byte(` + "`" + `{"timeouts": [{"route": "/api/**", "timeout": "soon"}]}` + "`" + `)); err == nil {
+		t.Error("Expected an invalid timeout to be rejected")
+	}
+}
+`,
+	})
+}
+
+func TestGeneratedGuard(t *testing.T) {
+	testGeneratedFiles(t, map[string]string{
+		"guard.go": generateGuardGo(),
+		"guard_test.go": `package main
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestGuard(t *testing.T) {
+	token, err := newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _ := newToken(); other == token || len(token) < 40 {
+		t.Fatalf("Expected random tokens, got %q and %q", token, other)
+	}
+	g := &requestGuard{token: token, host: "127.0.0.1:5173", origin: "http://127.0.0.1:5173"}
+
+	checks := []struct {
+		name      string
+		target    string
+		host      string
+		headers   map[string]string
+		needToken bool
+		want      error
+	}{
+		{"static file", "/index.html", "127.0.0.1:5173", nil, false, nil},
+		{"api with token", "/api/v1/items", "127.0.0.1:5173", map[string]string{tokenHeader: token, "Origin": "http://127.0.0.1:5173"}, true, nil},
+		{"api with token param", "/api/v1/events?govite_token=" + token, "127.0.0.1:5173", nil, true, nil},
+		{"api without token", "/api/v1/items", "127.0.0.1:5173", nil, true, errBadToken},
+		{"api with wrong token", "/api/v1/items", "127.0.0.1:5173", map[string]string{tokenHeader: "guess"}, true, errBadToken},
+		{"dns rebinding", "/api/v1/items", "evil.example.com:5173", map[string]string{tokenHeader: token}, true, errBadHost},
+		{"rebinding static file", "/index.html", "evil.example.com:5173", nil, false, errBadHost},
+		{"other origin", "/api/v1/items", "127.0.0.1:5173", map[string]string{tokenHeader: token, "Origin": "https://evil.example.com"}, true, errBadOrigin},
+		{"opaque origin", "/api/v1/items", "127.0.0.1:5173", map[string]string{tokenHeader: token, "Origin": "null"}, true, errBadOrigin},
+	}
+	for _, c := range checks {
+		r := httptest.NewRequest("GET", c.target, nil)
+		r.Host = c.host
+		for key, value := range c.headers {
+			r.Header.Set(key, value)
+		}
+		if _, err := g.check(r, c.needToken); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestCallerOrigin(t *testing.T) {
+	g := &requestGuard{token: "secret", host: "127.0.0.1:5173", origin: "http://127.0.0.1:5173"}
+	// A page cannot name its own origin, it only gets the app's with the token
+	for _, forged := range []string{"http://127.0.0.1:5173", "null", "", "secre", "secret "} {
+		if origin := g.callerOrigin(forged); origin != "" {
+			t.Errorf("callerOrigin(%q) = %q, want no origin", forged, origin)
+		}
+	}
+	if origin := g.callerOrigin("secret"); origin != "http://127.0.0.1:5173" {
+		t.Errorf("Expected the app origin for the token, got %q", origin)
+	}
+	if origin := (&requestGuard{origin: "null"}).callerOrigin(""); origin != "" {
+		t.Errorf("Expected no origin before there is a token, got %q", origin)
+	}
+}
+
+func TestAppDocument(t *testing.T) {
+	for origin, want := range map[string]string{
+		"http://127.0.0.1:5173": ` + "`" + `(location.origin === "http://127.0.0.1:5173")` + "`" + `,
+		"null":                  ` + "`" + `(location.origin === "null" && location.href === "about:blank")` + "`" + `,
+		"":                      "false",
+		` + "`" + `x"); alert(1); ("` + "`" + `: ` + "`" + `(location.origin === "x\"); alert(1); (\"")` + "`" + `,
+	} {
+		if got := appDocument(origin); got != want {
+			t.Errorf("appDocument(%q) = %s, want %s", origin, got, want)
+		}
+	}
+}
+
+func TestTokenScript(t *testing.T) {
+	script := tokenScript("abc", "http://127.0.0.1:5173")
+	if !strings.Contains(script, ` + "`" + `const token = "abc";` + "`" + `) || !strings.Contains(script, tokenHeader) {
+		t.Fatalf("Expected the token and header in the script, got:\n%s", script)
+	}
+	// Other pages the webview shows must not see the token
+	guard := ` + "`" + `if (!(location.origin === "http://127.0.0.1:5173")) return;` + "`" + `
+	if !strings.Contains(script, guard) || strings.Index(script, guard) > strings.Index(script, "const token") {
+		t.Fatalf("Expected the token to be kept to the app origin, got:\n%s", script)
+	}
+}
+
+func TestWithoutParam(t *testing.T) {
+	for query, want := range map[string]string{
+		"":                                    "",
+		"govite_token=x":                      "",
+		"q=go+vite&govite_token=x&page=2":     "q=go+vite&page=2",
+		"govite%5Ftoken=x&a=%2F&govite_token": "a=%2F",
+	} {
+		if got := withoutParam(query, tokenParam); got != want {
+			t.Errorf("withoutParam(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+`,
+	})
+}
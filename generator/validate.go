@@ -0,0 +1,410 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Validate parses every Go, JSON and TOML file in files, keyed as returned by
+// Files. The error of the first invalid file names its path, line and column.
+func Validate(files map[string]string) error {
+	paths := make([]string, 0, len(files))
+	for name := range files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+
+	for _, name := range paths {
+		var err error
+		switch {
+		case path.Ext(name) == ".go":
+			err = validateGo(name, files[name])
+		case path.Ext(name) == ".json" || path.Base(name) == ".prettierrc":
+			err = validateJSON(name, files[name])
+		case path.Ext(name) == ".toml":
+			err = validateTOML(name, files[name])
+		}
+		if err != nil {
+			return fmt.Errorf("invalid generated file %w", err)
+		}
+	}
+	return nil
+}
+
+func validateGo(name, src string) error {
+	if _, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ParseComments); err != nil {
+		return err
+	}
+	if _, err := format.Source([]byte(src)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func validateJSON(name, src string) error {
+	var v interface{}
+	err := json.Unmarshal([]byte(src), &v)
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := position(src, int(syntaxErr.Offset)-1)
+		return fmt.Errorf("%s:%d:%d: %v", name, line, col, err)
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// position returns the 1-based line and column of the byte at offset.
+func position(src string, offset int) (line, col int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(src) {
+		offset = len(src)
+	}
+	before := src[:offset]
+	line = strings.Count(before, "\n") + 1
+	col = offset - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+func validateTOML(name, src string) error {
+	p := &tomlParser{src: src}
+	if err := p.parse(); err != nil {
+		line, col := position(src, p.pos)
+		return fmt.Errorf("%s:%d:%d: %v", name, line, col, err)
+	}
+	return nil
+}
+
+// tomlParser checks TOML syntax. It does not build the document, so it does
+// not report semantic errors such as redefined keys.
+type tomlParser struct {
+	src string
+	pos int
+}
+
+var (
+	tomlBareKey  = regexp.MustCompile(`^[A-Za-z0-9_-]+`)
+	tomlBareWord = regexp.MustCompile(`^[0-9A-Za-z_+.:-]+`)
+	tomlScalar   = regexp.MustCompile(`^(true|false|[+-]?(inf|nan)|` +
+		`[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?|` +
+		`0x[0-9A-Fa-f](_?[0-9A-Fa-f])*|0o[0-7](_?[0-7])*|0b[01](_?[01])*|` +
+		`[0-9]{4}-[0-9]{2}-[0-9]{2}([Tt][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})?)?|` +
+		`[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?)$`)
+)
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) hasPrefix(s string) bool { return strings.HasPrefix(p.src[p.pos:], s) }
+
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments, as allowed inside arrays.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		switch {
+		case p.peek() == '#':
+			p.skipComment()
+		case p.peek() == '\n':
+			p.pos++
+		case p.hasPrefix("\r\n"):
+			p.pos += 2
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) expect(s string) error {
+	if !p.hasPrefix(s) {
+		return fmt.Errorf("expected %q", s)
+	}
+	p.pos += len(s)
+	return nil
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		var err error
+		switch {
+		case p.hasPrefix("[["):
+			p.pos += 2
+			err = p.parseHeader("]]")
+		case p.peek() == '[':
+			p.pos++
+			err = p.parseHeader("]")
+		default:
+			err = p.parseKeyValue()
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.expectLineEnd(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *tomlParser) parseHeader(close string) error {
+	p.skipSpace()
+	if err := p.parseKey(); err != nil {
+		return err
+	}
+	p.skipSpace()
+	return p.expect(close)
+}
+
+func (p *tomlParser) expectLineEnd() error {
+	p.skipSpace()
+	if p.peek() == '#' {
+		p.skipComment()
+	}
+	switch {
+	case p.eof():
+	case p.peek() == '\n':
+		p.pos++
+	case p.hasPrefix("\r\n"):
+		p.pos += 2
+	default:
+		return fmt.Errorf("unexpected %q after value", p.peek())
+	}
+	return nil
+}
+
+func (p *tomlParser) parseKeyValue() error {
+	if err := p.parseKey(); err != nil {
+		return err
+	}
+	p.skipSpace()
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	p.skipSpace()
+	return p.parseValue()
+}
+
+// parseKey parses a bare, quoted or dotted key.
+func (p *tomlParser) parseKey() error {
+	for {
+		switch p.peek() {
+		case '"':
+			if err := p.parseBasicString(); err != nil {
+				return err
+			}
+		case '\'':
+			if err := p.parseLiteralString(); err != nil {
+				return err
+			}
+		default:
+			key := tomlBareKey.FindString(p.src[p.pos:])
+			if key == "" {
+				return errors.New("expected a key")
+			}
+			p.pos += len(key)
+		}
+		p.skipSpace()
+		if p.peek() != '.' {
+			return nil
+		}
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+func (p *tomlParser) parseValue() error {
+	switch {
+	case p.hasPrefix(`"""`):
+		return p.parseMultilineString(`"""`, true)
+	case p.hasPrefix(`'''`):
+		return p.parseMultilineString(`'''`, false)
+	case p.peek() == '"':
+		return p.parseBasicString()
+	case p.peek() == '\'':
+		return p.parseLiteralString()
+	case p.peek() == '[':
+		return p.parseArray()
+	case p.peek() == '{':
+		return p.parseInlineTable()
+	}
+	word := tomlBareWord.FindString(p.src[p.pos:])
+	if word == "" {
+		return errors.New("expected a value")
+	}
+	if !tomlScalar.MatchString(word) {
+		return fmt.Errorf("invalid value %q", word)
+	}
+	p.pos += len(word)
+	return nil
+}
+
+func (p *tomlParser) parseArray() error {
+	p.pos++
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.pos++
+			return nil
+		}
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+		p.skipBlank()
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+		return p.expect("]")
+	}
+}
+
+func (p *tomlParser) parseInlineTable() error {
+	p.pos++
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return nil
+	}
+	for {
+		p.skipSpace()
+		if err := p.parseKeyValue(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+		return p.expect("}")
+	}
+}
+
+func (p *tomlParser) parseBasicString() error {
+	start := p.pos
+	p.pos++
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '"':
+			p.pos++
+			return nil
+		case c == '\\':
+			if err := p.parseEscape(false); err != nil {
+				return err
+			}
+			continue
+		case c == '\n' || c == '\r':
+			p.pos = start
+			return errors.New("unterminated string")
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			return fmt.Errorf("control character %#02x in string", c)
+		}
+		p.pos++
+	}
+	p.pos = start
+	return errors.New("unterminated string")
+}
+
+func (p *tomlParser) parseLiteralString() error {
+	start := p.pos
+	end := strings.IndexAny(p.src[p.pos+1:], "'\n")
+	if end < 0 || p.src[p.pos+1+end] != '\'' {
+		return errors.New("unterminated string")
+	}
+	p.pos += end + 2
+	if strings.ContainsAny(p.src[start:p.pos], "\r\x7f") {
+		p.pos = start
+		return errors.New("control character in string")
+	}
+	return nil
+}
+
+func (p *tomlParser) parseMultilineString(delim string, escapes bool) error {
+	start := p.pos
+	p.pos += len(delim)
+	for !p.eof() {
+		if p.hasPrefix(delim) {
+			p.pos += len(delim)
+			// Up to two quotes may directly precede the closing delimiter.
+			for i := 0; i < 2 && p.peek() == delim[0]; i++ {
+				p.pos++
+			}
+			return nil
+		}
+		if escapes && p.peek() == '\\' {
+			if err := p.parseEscape(true); err != nil {
+				return err
+			}
+			continue
+		}
+		p.pos++
+	}
+	p.pos = start
+	return errors.New("unterminated string")
+}
+
+// parseEscape parses the escape sequence at p.pos. A line-ending backslash is
+// only valid in multi-line basic strings.
+func (p *tomlParser) parseEscape(multiline bool) error {
+	p.pos++
+	c := p.peek()
+	switch c {
+	case 'b', 't', 'n', 'f', 'r', '"', '\\':
+		p.pos++
+		return nil
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		hex := p.src[p.pos+1:]
+		if len(hex) < n || strings.Trim(hex[:n], "0123456789abcdefABCDEF") != "" {
+			return fmt.Errorf(`invalid \%c escape`, c)
+		}
+		p.pos += 1 + n
+		return nil
+	case ' ', '\t', '\r', '\n':
+		if !multiline {
+			break
+		}
+		rest := strings.TrimLeft(p.src[p.pos:], " \t")
+		if !strings.HasPrefix(rest, "\n") && !strings.HasPrefix(rest, "\r\n") {
+			break
+		}
+		p.pos = len(p.src) - len(strings.TrimLeft(rest, " \t\r\n"))
+		return nil
+	}
+	p.pos--
+	return fmt.Errorf(`invalid escape \%c`, c)
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGeneratedProject(t *testing.T) {
+	if err := Validate(Files(testConfig())); err != nil {
+		t.Fatalf("Expected the generated project to be valid: %v", err)
+	}
+}
+
+func TestValidateReportsPosition(t *testing.T) {
+	cases := map[string]struct{ content, expected string }{
+		"main.go":      {"package main\n\nfunc main() {\n\tx :=\n}\n", "main.go:5:1:"},
+		"package.json": {"{\n  \"name\": \"a\" \"b\"\n}\n", "package.json:2:15:"},
+		".prettierrc":  {"{\n  \"semi\": true,\n}\n", ".prettierrc:3:1:"},
+		"netlify.toml": {"[build]\n  publish = \"dist\n", "netlify.toml:2:13: unterminated string"},
+		"a/b.toml":     {"x = 1\ny = nope\n", "a/b.toml:2:5: invalid value"},
+	}
+	for name, c := range cases {
+		err := Validate(map[string]string{name: c.content})
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Fatalf("Expected %q in the error for %s, got %v", c.expected, name, err)
+		}
+	}
+}
+
+func TestValidateTOML(t *testing.T) {
+	valid := `# comment
+title = "TOML \"x\" \u00e9"
+'literal key' = 'C:\path'
+a.b."c" = 1_000
+[servers.alpha]
+ip = "10.0.0.1" # trailing
+ports = [ 8000, 8001,
+  8002, ]
+when = 1979-05-27T07:32:00Z
+point = { x = 1, y = -2.5e3 }
+text = """
+multi \
+  line"""
+raw = '''
+as is \ '''
+[[products]]
+ok = true
+`
+	if err := validateTOML("valid.toml", valid); err != nil {
+		t.Fatalf("Expected valid TOML, got %v", err)
+	}
+	for _, invalid := range []string{
+		"a = \"\\q\"\n",
+		"a = 1 b = 2\n",
+		"[table\n",
+		"a = [1, 2\n",
+		"= 1\n",
+	} {
+		if err := validateTOML("invalid.toml", invalid); err == nil {
+			t.Fatalf("Expected %q to be rejected", invalid)
+		}
+	}
+}
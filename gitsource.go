@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitSource is a module in a git repository, written as
+//
+//	git+https://host/org/repo//subdir@ref
+//	file:///srv/git/repo.git//subdir@ref
+//
+// The subdirectory and ref are optional. Ref is a branch, tag or commit and
+// defaults to the repository's HEAD. Only the https, ssh, git and file
+// schemes are accepted, so a source cannot select another git transport.
+type gitSource struct {
+	URL    string
+	Subdir string
+	Ref    string
+}
+
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+var gitSchemes = map[string]bool{"https": true, "ssh": true, "git": true, "file": true}
+
+// isGitSource reports whether an install-local argument names a git
+// repository rather than a directory.
+func isGitSource(arg string) bool {
+	return strings.HasPrefix(arg, "git+") || strings.HasPrefix(arg, "file://")
+}
+
+func parseGitSource(arg string) (gitSource, error) {
+	raw := strings.TrimPrefix(arg, "git+")
+	scheme := strings.Index(raw, "://")
+	if scheme <= 0 {
+		return gitSource{}, fmt.Errorf("invalid git source %q", arg)
+	}
+	if !gitSchemes[raw[:scheme]] {
+		return gitSource{}, fmt.Errorf("unsupported scheme %q in git source %q (expected https, ssh, git or file)", raw[:scheme], arg)
+	}
+
+	// The path starts after the host, so user@host is not taken for a ref
+	pathStart := scheme + len("://")
+	if i := strings.IndexByte(raw[pathStart:], '/'); i >= 0 {
+		pathStart += i
+	} else {
+		pathStart = len(raw)
+	}
+
+	src := gitSource{}
+	if i := strings.LastIndexByte(raw[pathStart:], '@'); i >= 0 {
+		src.Ref = raw[pathStart+i+1:]
+		raw = raw[:pathStart+i]
+		if src.Ref == "" || strings.HasPrefix(src.Ref, "-") {
+			return gitSource{}, fmt.Errorf("invalid ref in git source %q", arg)
+		}
+	}
+	if i := strings.Index(raw[pathStart:], "//"); i >= 0 {
+		src.Subdir = strings.Trim(raw[pathStart+i+2:], "/")
+		raw = raw[:pathStart+i]
+		if src.Subdir == "" || !isRelativeSubpath(src.Subdir) {
+			return gitSource{}, fmt.Errorf("invalid subdirectory in git source %q", arg)
+		}
+	}
+	src.URL = raw
+	return src, nil
+}
+
+// String returns the source in the form parseGitSource accepts.
+func (s gitSource) String() string {
+	str := s.URL
+	if !strings.HasPrefix(str, "file://") {
+		str = "git+" + str
+	}
+	if s.Subdir != "" {
+		str += "//" + s.Subdir
+	}
+	if s.Ref != "" {
+		str += "@" + s.Ref
+	}
+	return str
+}
+
+func gitCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "go-vite", "git")
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// mirrorGitRepo clones url into the cache as a bare mirror, or fetches into
+// the existing mirror, and returns the mirror's directory.
+func mirrorGitRepo(url string) (string, error) {
+	sum := sha256.Sum256([]byte(url))
+	mirror := filepath.Join(gitCacheDir(), "repos", hex.EncodeToString(sum[:8])+".git")
+	if _, err := os.Stat(mirror); err == nil {
+		if _, err := runGit(mirror, "fetch", "--quiet", "--prune", "--tags", "origin"); err != nil {
+			return "", err
+		}
+		return mirror, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(mirror), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(mirror), ".clone-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if _, err := runGit("", "clone", "--quiet", "--mirror", "--", url, tmp); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, mirror); err != nil {
+		return "", err
+	}
+	return mirror, nil
+}
+
+// checkoutGitSource resolves the source's ref to a commit and returns a
+// checkout of the module directory at that commit. Checkouts are cached by
+// commit and never modified.
+func checkoutGitSource(src gitSource) (dir, commit string, err error) {
+	// A pinned commit already in the cache needs no network access
+	if commitPattern.MatchString(src.Ref) {
+		if dir, ok := cachedGitCheckout(src, src.Ref); ok {
+			return dir, src.Ref, nil
+		}
+	}
+
+	mirror, err := mirrorGitRepo(src.URL)
+	if err != nil {
+		return "", "", err
+	}
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	// Refs from govite.lock and update skip parseGitSource, and git would
+	// read a leading dash as an option
+	if strings.HasPrefix(ref, "-") {
+		return "", "", fmt.Errorf("invalid ref %q", ref)
+	}
+	commit, err = runGit(mirror, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", "", fmt.Errorf("ref %q not found in %s", ref, src.URL)
+	}
+
+	checkout := filepath.Join(gitCacheDir(), "checkouts", commit)
+	if _, err := os.Stat(checkout); err != nil {
+		if err := os.MkdirAll(filepath.Dir(checkout), 0755); err != nil {
+			return "", "", err
+		}
+		tmp, err := os.MkdirTemp(filepath.Dir(checkout), ".checkout-*")
+		if err != nil {
+			return "", "", err
+		}
+		defer os.RemoveAll(tmp)
+		if _, err := runGit("", "clone", "--quiet", "--shared", "--no-checkout", "--", mirror, tmp); err != nil {
+			return "", "", err
+		}
+		if _, err := runGit(tmp, "checkout", "--quiet", "--detach", commit); err != nil {
+			return "", "", err
+		}
+		if err := os.Rename(tmp, checkout); err != nil {
+			return "", "", err
+		}
+	}
+
+	dir, ok := cachedGitCheckout(src, commit)
+	if !ok {
+		return "", "", fmt.Errorf("%s has no directory %s at %s", src.URL, src.Subdir, commit)
+	}
+	return dir, commit, nil
+}
+
+// cachedGitCheckout returns the module directory inside the cached checkout
+// of commit, if there is one.
+func cachedGitCheckout(src gitSource, commit string) (string, bool) {
+	dir := filepath.Join(gitCacheDir(), "checkouts", commit, filepath.FromSlash(src.Subdir))
+	info, err := os.Stat(dir)
+	return dir, err == nil && info.IsDir()
+}
+
+// installGitModule checks out a git source and installs it like a local
+// module, recording the source and the exact commit in govite.lock.
+func installGitModule(arg string, opts installOptions) error {
+	src, err := parseGitSource(arg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Fetching %s\n", src)
+	dir, commit, err := checkoutGitSource(src)
+	if err != nil {
+		return err
+	}
+	opts.Source = "git"
+	opts.Origin = src.String()
+	opts.Commit = commit
+	return installLocal(dir, opts)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitSource(t *testing.T) {
+	cases := map[string]gitSource{
+		"git+https://host/org/repo//modules/csv@v1.2.0": {URL: "https://host/org/repo", Subdir: "modules/csv", Ref: "v1.2.0"},
+		"git+https://host/org/repo":                     {URL: "https://host/org/repo"},
+		"git+ssh://git@host/org/repo.git@main":          {URL: "ssh://git@host/org/repo.git", Ref: "main"},
+		"file:///srv/git/repo.git//widgets":             {URL: "file:///srv/git/repo.git", Subdir: "widgets"},
+	}
+	for arg, expected := range cases {
+		got, err := parseGitSource(arg)
+		if err != nil {
+			t.Fatalf("parseGitSource(%q) failed: %v", arg, err)
+		}
+		if got != expected {
+			t.Fatalf("parseGitSource(%q) = %+v, expected %+v", arg, got, expected)
+		}
+		if got.String() != arg {
+			t.Fatalf("Expected %q to round-trip, got %q", arg, got.String())
+		}
+	}
+
+	rejected := []string{
+		"git+nothing",
+		"git+https://host/repo@",
+		"git+https://host/repo//../up",
+		"git+--upload-pack=touch /tmp/pwned;://x",
+		"git+ext::sh -c touch% /tmp/pwned://x",
+		"git+http://host/repo",
+		"git+https://host/repo@--output=/tmp/pwned",
+	}
+	for _, arg := range rejected {
+		if _, err := parseGitSource(arg); err == nil {
+			t.Fatalf("Expected %q to be rejected", arg)
+		}
+	}
+}
+
+// git runs git in dir with a fixed identity and fails the test on error.
+func git(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// newTestGitRepo creates a bare repository with a Node module in
+// packages/widgets, tagged v1.0.0, and returns its path and a work tree.
+func newTestGitRepo(t *testing.T) (string, string) {
+	work := t.TempDir()
+	git(t, work, "init", "--quiet", "--initial-branch=main")
+	moduleDir := filepath.Join(work, "packages", "widgets")
+	os.MkdirAll(moduleDir, 0755)
+	writeTestNodeModule(t, moduleDir, "widgets", "1.0.0")
+	os.WriteFile(filepath.Join(work, ".gitignore"), []byte("node_modules/\n"), 0644)
+	git(t, work, "add", "-A")
+	git(t, work, "commit", "--quiet", "-m", "widgets 1.0.0")
+	git(t, work, "tag", "v1.0.0")
+
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	git(t, "", "clone", "--quiet", "--bare", work, bare)
+	git(t, work, "remote", "add", "origin", bare)
+	return bare, work
+}
+
+func TestInstallGitModule(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	bare, work := newTestGitRepo(t)
+	tagged := git(t, work, "rev-parse", "v1.0.0")
+
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	arg := "file://" + filepath.ToSlash(bare) + "//packages/widgets@v1.0.0"
+	if err := installGitModule(arg, installOptions{}); err != nil {
+		t.Fatalf("installGitModule failed: %v", err)
+	}
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+	if _, err := os.Stat(filepath.Join(modulePath, "index.js")); err != nil {
+		t.Fatal("Expected the subdirectory to be installed")
+	}
+	if _, err := os.Stat(filepath.Join(modulePath, "packages")); !os.IsNotExist(err) {
+		t.Fatal("Expected only the subdirectory to be installed")
+	}
+
+	lock, _ := loadLockfile()
+	entry := lock.find("widgets")
+	if entry == nil || entry.Source != "git" || entry.Origin != arg || entry.Commit != tagged {
+		t.Fatalf("Expected the git source and commit %s in the lock, got %+v", tagged, entry)
+	}
+
+	// Moving the tag upstream does not change what a frozen install restores
+	os.WriteFile(filepath.Join(work, "packages", "widgets", "index.js"), []byte("module.exports = 2\n"), 0644)
+	git(t, work, "commit", "--quiet", "-am", "widgets 2")
+	git(t, work, "tag", "-f", "v1.0.0")
+	git(t, work, "push", "--quiet", "--force", "origin", "main", "v1.0.0")
+
+	os.RemoveAll(modulePath)
+	if err := installFrozen(); err != nil {
+		t.Fatalf("installFrozen failed: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(modulePath, "index.js"))
+	if string(content) != "module.exports = {}\n" {
+		t.Fatalf("Expected the locked commit to be reinstalled, got %q", content)
+	}
+}
+
+func TestCheckoutGitSourceUnknownRef(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	bare, _ := newTestGitRepo(t)
+
+	_, _, err := checkoutGitSource(gitSource{URL: "file://" + filepath.ToSlash(bare), Ref: "v9.9.9"})
+	if err == nil || !strings.Contains(err.Error(), `ref "v9.9.9" not found`) {
+		t.Fatalf("Expected an unknown ref error, got %v", err)
+	}
+	_, _, err = checkoutGitSource(gitSource{URL: "file://" + filepath.ToSlash(bare), Ref: "--output=" + filepath.Join(t.TempDir(), "out")})
+	if err == nil || !strings.Contains(err.Error(), "invalid ref") {
+		t.Fatalf("Expected a ref that looks like an option to be rejected, got %v", err)
+	}
+	_, _, err = checkoutGitSource(gitSource{URL: "file://" + filepath.ToSlash(bare), Subdir: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "has no directory missing") {
+		t.Fatalf("Expected a missing subdirectory error, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The helpers below make small line-based edits to generated Go files such as
+// builtin.go and routes.go. They only rely on gofmt layout, which is what the
+// generator emits, so they don't need a full Go parser.
+
+// addLineToFunc appends line to the end of the body of funcName in path.
+// Lines that are already present are not added twice.
+func addLineToFunc(path, funcName, line string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(content)
+	if strings.Contains(src, line+"\n") {
+		return nil
+	}
+
+	start := strings.Index(src, "func "+funcName+"(")
+	if start == -1 {
+		return fmt.Errorf("%s not found in %s", funcName, path)
+	}
+	end := strings.Index(src[start:], "\n}")
+	if end == -1 {
+		return fmt.Errorf("malformed %s in %s", funcName, path)
+	}
+	end += start + 1
+
+	src = src[:end] + line + "\n" + src[end:]
+	return os.WriteFile(path, []byte(src), 0644)
+}
+
+// removeLinesWithPrefix deletes every line of path whose trimmed content
+// starts with prefix. A missing file is not an error.
+func removeLinesWithPrefix(path, prefix string) error {
+	return removeLines(path, func(line string) bool {
+		return strings.HasPrefix(strings.TrimSpace(line), prefix)
+	})
+}
+
+// removeLinesContaining deletes every line of path that contains substr. A
+// missing file is not an error.
+func removeLinesContaining(path, substr string) error {
+	return removeLines(path, func(line string) bool {
+		return strings.Contains(line, substr)
+	})
+}
+
+func removeLines(path string, match func(string) bool) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var kept []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if match(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return os.WriteFile(path, []byte(strings.Join(kept, "\n")), 0644)
+}
+
+// importLine renders an import spec as it appears inside an import block.
+func importLine(alias, importPath string) string {
+	return fmt.Sprintf("\t%s %q", alias, importPath)
+}
+
+// addGoImport adds a named import to the Go file at path, creating an import
+// block if the file has none.
+func addGoImport(path, alias, importPath string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(content)
+	line := importLine(alias, importPath)
+	if strings.Contains(src, line+"\n") {
+		return nil
+	}
+
+	if idx := strings.Index(src, "import (\n"); idx != -1 {
+		idx += len("import (\n")
+		src = src[:idx] + line + "\n" + src[idx:]
+	} else if idx := strings.Index(src, "\nimport \""); idx != -1 {
+		end := strings.Index(src[idx+1:], "\n")
+		single := strings.TrimPrefix(src[idx+1:idx+1+end], "import ")
+		src = src[:idx+1] + "import (\n\t" + single + "\n" + line + "\n)" + src[idx+1+end:]
+	} else {
+		pkgEnd := strings.Index(src, "\n")
+		if pkgEnd == -1 || !strings.HasPrefix(src, "package ") {
+			return fmt.Errorf("no package clause in %s", path)
+		}
+		src = src[:pkgEnd+1] + "\nimport (\n" + line + "\n)\n" + src[pkgEnd+1:]
+	}
+	return os.WriteFile(path, []byte(src), 0644)
+}
+
+// removeGoImport removes an import added by addGoImport and drops the import
+// block altogether if it ends up empty.
+func removeGoImport(path, alias, importPath string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	src := strings.Replace(string(content), importLine(alias, importPath)+"\n", "", 1)
+	src = strings.Replace(src, "\nimport (\n)\n", "", 1)
+	return os.WriteFile(path, []byte(src), 0644)
+}
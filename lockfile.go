@@ -0,0 +1,435 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	lockFileName    = "govite.lock"
+	lockFileVersion = 1
+	treeHashPrefix  = "sha256:"
+)
+
+// Lockfile records every module copied into the project so the exact set can
+// be verified and reproduced. It lives at the project root as govite.lock.
+type Lockfile struct {
+	Version int         `json:"version"`
+	Modules []LockEntry `json:"modules"`
+}
+
+// LockEntry describes one installed module.
+type LockEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Source is how the module was installed: local, imported or registry.
+	Source string `json:"source"`
+	// Origin is the directory, registry or git source the module was
+	// installed from.
+	Origin  string `json:"origin"`
+	Version string `json:"version,omitempty"`
+	// Commit is the exact commit of a module installed from git.
+	Commit string `json:"commit,omitempty"`
+	// Path is where the module was copied to, relative to the project root.
+	Path  string `json:"path"`
+	Hash  string `json:"hash"`
+	Alias string `json:"alias,omitempty"`
+	// Dependencies are the version ranges the module requires of other
+	// modules, copied from its manifest.
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+}
+
+func loadLockfile() (*Lockfile, error) {
+	lock := &Lockfile{Version: lockFileVersion}
+	content, err := os.ReadFile(lockFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(content, lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockFileName, err)
+	}
+	if lock.Version != lockFileVersion {
+		return nil, fmt.Errorf("unsupported %s version %d", lockFileName, lock.Version)
+	}
+	for _, entry := range lock.Modules {
+		if err := checkLockPath(entry); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", lockFileName, err)
+		}
+	}
+	return lock, nil
+}
+
+// checkLockPath makes sure an entry's path is where go-vite installs that
+// module. Installs delete and rewrite the path, and the lock may come from
+// someone else, so it must never point elsewhere.
+func checkLockPath(entry LockEntry) error {
+	p := entry.Path
+	want, err := moduleDestination(entry.Name, parseLockModuleType(entry.Type))
+	if err != nil {
+		return err
+	}
+	if p != filepath.ToSlash(want) {
+		return fmt.Errorf("module %s has path %q, expected %s", entry.Name, p, filepath.ToSlash(want))
+	}
+	return nil
+}
+
+// moduleDestination is getModuleDestinationPath for a name that may come
+// from an untrusted file. It fails unless the path is strictly inside
+// backend/internal/modules or frontend/modules.
+func moduleDestination(moduleName string, moduleType ProjectType) (string, error) {
+	p := filepath.ToSlash(getModuleDestinationPath(moduleName, moduleType))
+	valid := !path.IsAbs(p) && !strings.Contains(moduleName, `\`) &&
+		(strings.HasPrefix(p, "backend/internal/modules/") || strings.HasPrefix(p, frontendModulesDir+"/"))
+	for _, part := range strings.Split(moduleName, "/") {
+		if part == ".." || part == "." || part == "" {
+			valid = false
+		}
+	}
+	if !valid {
+		return "", fmt.Errorf("invalid module name %q", moduleName)
+	}
+	return filepath.FromSlash(p), nil
+}
+
+func (l *Lockfile) save() error {
+	sort.Slice(l.Modules, func(i, j int) bool { return l.Modules[i].Name < l.Modules[j].Name })
+	content, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockFileName, append(content, '\n'), 0644)
+}
+
+func (l *Lockfile) find(name string) *LockEntry {
+	for i := range l.Modules {
+		if l.Modules[i].Name == name {
+			return &l.Modules[i]
+		}
+	}
+	return nil
+}
+
+func (l *Lockfile) upsert(entry LockEntry) {
+	if existing := l.find(entry.Name); existing != nil {
+		*existing = entry
+		return
+	}
+	l.Modules = append(l.Modules, entry)
+}
+
+func (l *Lockfile) remove(name string) {
+	for i := range l.Modules {
+		if l.Modules[i].Name == name {
+			l.Modules = append(l.Modules[:i], l.Modules[i+1:]...)
+			return
+		}
+	}
+}
+
+func lockModuleType(moduleType ProjectType) string {
+	switch moduleType {
+	case GoProject:
+		return "go"
+	case NodeProject:
+		return "node"
+	default:
+		return "unknown"
+	}
+}
+
+func parseLockModuleType(s string) ProjectType {
+	switch s {
+	case "go":
+		return GoProject
+	case "node":
+		return NodeProject
+	default:
+		return Unknown
+	}
+}
+
+// hashModuleTree returns a content hash of the tree at dir, leaving out the
+// paths module copies ignore. It covers the relative path, executable bit
+// and contents of every file, and the target of every symlink, so it changes
+// on any edit, addition or removal.
+func hashModuleTree(dir string) (string, error) {
+	return hashModuleTreeExcept(dir, "")
+}
+
+// hashModuleTreeExcept hashes like hashModuleTree but leaves out the file at
+// the slash-separated path skip.
+func hashModuleTreeExcept(dir, skip string) (string, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	err = walkModuleTree(root, func(rel string, info os.FileInfo) error {
+		if rel == skip {
+			return nil
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := symlinkTarget(root, rel)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, fmt.Sprintf("link %s %s", rel, filepath.ToSlash(target)))
+		case info.Mode().IsRegular():
+			file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+			if err != nil {
+				return err
+			}
+			h := sha256.New()
+			_, err = io.Copy(h, file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			kind := "file"
+			if info.Mode()&0111 != 0 {
+				kind = "exec"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %x", kind, rel, h.Sum(nil)))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return treeHashPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// moduleVersion returns the version a module declares in its manifest or,
+// for Node modules, its package.json.
+func moduleVersion(dir string, moduleType ProjectType) string {
+	if manifest, err := loadModuleManifest(dir); err == nil && manifest != nil && manifest.Version != "" {
+		return manifest.Version
+	}
+	if moduleType == NodeProject {
+		if pkg, err := readNodePackage(dir); err == nil {
+			return pkg.Version
+		}
+	}
+	return ""
+}
+
+// recordLockEntry hashes the installed module and writes it to govite.lock.
+func recordLockEntry(moduleName string, moduleType ProjectType, modulePath string, opts installOptions) error {
+	hash, err := hashModuleTree(modulePath)
+	if err != nil {
+		return err
+	}
+	lock, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+	version := opts.Version
+	if version == "" {
+		version = moduleVersion(modulePath, moduleType)
+	}
+	var dependencies map[string]string
+	if manifest, err := loadModuleManifest(modulePath); err == nil && manifest != nil {
+		dependencies = manifest.Dependencies
+	}
+	lock.upsert(LockEntry{
+		Name:         moduleName,
+		Type:         lockModuleType(moduleType),
+		Source:       opts.source(),
+		Origin:       opts.Origin,
+		Version:      version,
+		Commit:       opts.Commit,
+		Path:         filepath.ToSlash(modulePath),
+		Hash:         hash,
+		Alias:        opts.Alias,
+		Dependencies: dependencies,
+	})
+	return lock.save()
+}
+
+// removeLockEntry drops a module from govite.lock if it is recorded there.
+func removeLockEntry(moduleName string) error {
+	if _, err := os.Stat(lockFileName); os.IsNotExist(err) {
+		return nil
+	}
+	lock, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+	lock.remove(moduleName)
+	return lock.save()
+}
+
+// lockProblem is a module whose installed tree doesn't match the lock.
+type lockProblem struct {
+	Name   string
+	Reason string
+}
+
+// verifyLockfile compares every locked module against its installed tree.
+func verifyLockfile(lock *Lockfile) []lockProblem {
+	var problems []lockProblem
+	for _, entry := range lock.Modules {
+		path := filepath.FromSlash(entry.Path)
+		if _, err := os.Stat(path); err != nil {
+			problems = append(problems, lockProblem{entry.Name, fmt.Sprintf("missing (%s)", entry.Path)})
+			continue
+		}
+		hash, err := hashModuleTree(path)
+		if err != nil {
+			problems = append(problems, lockProblem{entry.Name, err.Error()})
+			continue
+		}
+		if hash != entry.Hash {
+			problems = append(problems, lockProblem{entry.Name, fmt.Sprintf("modified (expected %s, got %s)", entry.Hash, hash)})
+		}
+	}
+	return problems
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	lock, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+	if len(lock.Modules) == 0 {
+		fmt.Printf("No modules recorded in %s\n", lockFileName)
+		return nil
+	}
+
+	problems := verifyLockfile(lock)
+	bad := make(map[string]bool)
+	for _, problem := range problems {
+		bad[problem.Name] = true
+		fmt.Printf("❌ %s: %s\n", problem.Name, problem.Reason)
+	}
+	for _, entry := range lock.Modules {
+		if !bad[entry.Name] {
+			fmt.Printf("✅ %s %s\n", entry.Name, entry.Version)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d of %d modules do not match %s", len(problems), len(lock.Modules), lockFileName)
+	}
+	return nil
+}
+
+// installFrozen reinstalls every module in govite.lock whose installed tree
+// is missing or differs, and fails if the source no longer produces the
+// locked content.
+func installFrozen() error {
+	if _, err := os.Stat(lockFileName); os.IsNotExist(err) {
+		return fmt.Errorf("%s not found", lockFileName)
+	}
+	lock, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+
+	problems := verifyLockfile(lock)
+	if len(problems) == 0 {
+		fmt.Printf("All %d modules match %s\n", len(lock.Modules), lockFileName)
+		return nil
+	}
+
+	for _, problem := range problems {
+		entry := *lock.find(problem.Name)
+		fmt.Printf("Reinstalling %s %s (%s)\n", entry.Name, entry.Version, problem.Reason)
+
+		sourcePath, err := lockedSourcePath(entry)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		if err := checkSourceHash(entry, sourcePath); err != nil {
+			return err
+		}
+
+		if err := os.RemoveAll(filepath.FromSlash(entry.Path)); err != nil {
+			return err
+		}
+		err = installLocal(sourcePath, installOptions{
+			Alias:            entry.Alias,
+			Source:           entry.Source,
+			Origin:           entry.Origin,
+			Version:          entry.Version,
+			Commit:           entry.Commit,
+			SkipDependencies: true,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+	}
+
+	// Installing rewrites the lock; it must come out identical
+	after, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+	for _, entry := range lock.Modules {
+		if got := after.find(entry.Name); got == nil || got.Hash != entry.Hash {
+			return fmt.Errorf("%s: reinstalled content does not match %s", entry.Name, lockFileName)
+		}
+	}
+	return nil
+}
+
+// lockedSourcePath returns a local directory holding the locked version of a
+// module, fetching it from its registry if necessary.
+func lockedSourcePath(entry LockEntry) (string, error) {
+	switch entry.Source {
+	case "registry":
+	case "git":
+		src, err := parseGitSource(entry.Origin)
+		if err != nil {
+			return "", err
+		}
+		src.Ref = entry.Commit
+		dir, _, err := checkoutGitSource(src)
+		return dir, err
+	default:
+		return entry.Origin, nil
+	}
+	source, module, v, err := resolveRegistryModule([]string{entry.Origin}, entry.Name, entry.Version)
+	if err != nil {
+		return "", err
+	}
+	if module == nil || v.Version != entry.Version {
+		return "", fmt.Errorf("version %s is no longer published at %s", entry.Version, entry.Origin)
+	}
+	return fetchRegistryModule(source, module.Name, v)
+}
+
+// checkSourceHash makes sure the source would reproduce the locked tree
+// before anything in the project is touched.
+func checkSourceHash(entry LockEntry, sourcePath string) error {
+	if parseLockModuleType(entry.Type) != detectLocalModuleType(sourcePath) {
+		return fmt.Errorf("%s: source %s is not a %s module", entry.Name, sourcePath, entry.Type)
+	}
+	hash, err := hashModuleTree(sourcePath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", entry.Name, err)
+	}
+	if hash != entry.Hash {
+		return fmt.Errorf("%s: source %s has changed since it was locked (expected %s, got %s)",
+			entry.Name, sourcePath, entry.Hash, hash)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashModuleTree(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0644)
+
+	base, err := hashModuleTree(dir)
+	if err != nil {
+		t.Fatalf("hashModuleTree failed: %v", err)
+	}
+	if !strings.HasPrefix(base, treeHashPrefix) {
+		t.Fatalf("Expected %s prefix, got %s", treeHashPrefix, base)
+	}
+
+	copyPath := filepath.Join(t.TempDir(), "copy")
+	copyDir(dir, copyPath)
+	if hash, _ := hashModuleTree(copyPath); hash != base {
+		t.Fatal("Expected a copy to hash the same")
+	}
+
+	changes := []func(){
+		func() { os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644) },
+		func() { os.WriteFile(filepath.Join(dir, "new.txt"), []byte(""), 0644) },
+		func() { os.Remove(filepath.Join(dir, "sub", "b.txt")) },
+		func() { os.Chmod(filepath.Join(dir, "a.txt"), 0755) },
+	}
+	previous := base
+	for i, change := range changes {
+		change()
+		hash, _ := hashModuleTree(dir)
+		if hash == previous {
+			t.Fatalf("Expected change %d to alter the hash", i)
+		}
+		previous = hash
+	}
+}
+
+// installTestNodeModule installs a Node module into the current project via
+// install-local and returns its source directory.
+func installTestNodeModule(t *testing.T, name string) string {
+	source := t.TempDir()
+	writeTestNodeModule(t, source, name, "1.0.0")
+	installLocalModule(source, installOptions{Alias: "@" + name})
+	return source
+}
+
+func TestInstallLocalRecordsLockEntry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := installTestNodeModule(t, "widgets")
+
+	lock, err := loadLockfile()
+	if err != nil {
+		t.Fatalf("loadLockfile failed: %v", err)
+	}
+	entry := lock.find("widgets")
+	if entry == nil {
+		t.Fatalf("Expected widgets in %s", lockFileName)
+	}
+	if entry.Source != "local" || entry.Origin != source || entry.Version != "1.0.0" || entry.Type != "node" {
+		t.Fatalf("Unexpected lock entry: %+v", entry)
+	}
+	if entry.Path != "frontend/modules/widgets" || entry.Alias != "@widgets" {
+		t.Fatalf("Unexpected lock entry: %+v", entry)
+	}
+
+	if problems := verifyLockfile(lock); len(problems) != 0 {
+		t.Fatalf("Expected a clean verify, got %+v", problems)
+	}
+
+	os.WriteFile(filepath.Join(frontendModulesDir, "widgets", "index.js"), []byte("tampered"), 0644)
+	problems := verifyLockfile(lock)
+	if len(problems) != 1 || !strings.Contains(problems[0].Reason, "modified") {
+		t.Fatalf("Expected a modified module, got %+v", problems)
+	}
+
+	os.RemoveAll(filepath.Join(frontendModulesDir, "widgets"))
+	problems = verifyLockfile(lock)
+	if len(problems) != 1 || !strings.Contains(problems[0].Reason, "missing") {
+		t.Fatalf("Expected a missing module, got %+v", problems)
+	}
+}
+
+func TestInstallFrozen(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := installTestNodeModule(t, "widgets")
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+
+	// Local edits are reverted to the locked content
+	os.WriteFile(filepath.Join(modulePath, "index.js"), []byte("edited"), 0644)
+	os.WriteFile(filepath.Join(modulePath, "extra.js"), []byte("extra"), 0644)
+	if err := installFrozen(); err != nil {
+		t.Fatalf("installFrozen failed: %v", err)
+	}
+	lock, _ := loadLockfile()
+	if problems := verifyLockfile(lock); len(problems) != 0 {
+		t.Fatalf("Expected frozen install to restore the module, got %+v", problems)
+	}
+	if _, err := os.Stat(filepath.Join(modulePath, "extra.js")); !os.IsNotExist(err) {
+		t.Fatal("Expected stale files to be removed")
+	}
+
+	// A source that drifted from the lock must not be installed
+	os.RemoveAll(modulePath)
+	os.WriteFile(filepath.Join(source, "index.js"), []byte("new release"), 0644)
+	err := installFrozen()
+	if err == nil || !strings.Contains(err.Error(), "changed since it was locked") {
+		t.Fatalf("Expected drifted source to be rejected, got %v", err)
+	}
+}
+
+func TestInstallFrozenReturnsInstallErrors(t *testing.T) {
+	setupSigning(t, policyOff)
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	installTestNodeModule(t, "widgets")
+	os.RemoveAll(filepath.Join(frontendModulesDir, "widgets"))
+
+	// The unsigned source now fails the policy instead of exiting
+	data, _ := loadData()
+	data.SignaturePolicy = policyEnforce
+	saveData(data)
+	err := installFrozen()
+	if err == nil || !strings.Contains(err.Error(), "widgets: ") || !strings.Contains(err.Error(), "refusing to install") {
+		t.Fatalf("Expected the reinstall to fail, got %v", err)
+	}
+}
+
+func TestInstallFrozenWithoutLockfile(t *testing.T) {
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	if err := installFrozen(); err == nil {
+		t.Fatalf("Expected an error without %s", lockFileName)
+	}
+}
+
+func TestInstallLocalRejectsHostileNames(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := t.TempDir()
+	writeTestNodeModule(t, source, "../../x", "1.0.0")
+	err := installLocal(source, installOptions{})
+	if err == nil || !strings.Contains(err.Error(), "invalid module name") {
+		t.Fatalf("Expected the name to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "x")); !os.IsNotExist(err) {
+		t.Fatal("Expected nothing to be written outside frontend/modules")
+	}
+}
+
+func TestInstallFrozenRejectsHostilePaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	precious := t.TempDir()
+	os.WriteFile(filepath.Join(precious, "keep.txt"), []byte("keep"), 0644)
+	for _, hostile := range []string{
+		precious,
+		"frontend/modules/../../outside",
+		"frontend/modules/other",
+		"frontend/modules",
+		"backend/internal/modules/widgets",
+	} {
+		lock := `{"version": 1, "modules": [{"name": "widgets", "type": "node", "source": "local", "origin": "` +
+			filepath.ToSlash(precious) + `", "path": "` + filepath.ToSlash(hostile) + `", "hash": "sha256:00"}]}`
+		os.WriteFile(lockFileName, []byte(lock), 0644)
+
+		if _, err := loadLockfile(); err == nil || !strings.Contains(err.Error(), "expected frontend/modules/widgets") {
+			t.Errorf("Expected path %q to be rejected, got %v", hostile, err)
+		}
+		if err := installFrozen(); err == nil {
+			t.Errorf("Expected install --frozen to refuse path %q", hostile)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(precious, "keep.txt")); err != nil {
+		t.Fatalf("Expected the directory outside the project to survive: %v", err)
+	}
+}
@@ -94,6 +94,32 @@ func TestRunInitDirectoryExists(t *testing.T) {
 	}
 }
 
+func TestRunInitInProcessBackend(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	defer os.Chdir(oldWd)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("backend", "sidecar", "")
+	cmd.Flags().Set("backend", "inprocess")
+	if err := runInit(cmd, []string{"inproc-app"}); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join("inproc-app", "backend.go"))
+	if err != nil {
+		t.Fatalf("Expected backend.go: %v", err)
+	}
+	if !strings.Contains(string(content), "server.Mount") || strings.Contains(string(content), "go:embed bin/backend") {
+		t.Fatalf("Expected an in-process backend, got:\n%s", content)
+	}
+
+	cmd.Flags().Set("backend", "thread")
+	if err := runInit(cmd, []string{"thread-app"}); err == nil {
+		t.Fatal("Expected an unknown backend mode to fail")
+	}
+}
+
 func TestCreateProjectStructure(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "govite-test-*")
 	if err != nil {
@@ -143,371 +169,6 @@ func TestCreateProjectStructure(t *testing.T) {
 	}
 }
 
-func TestGenerateGoMod(t *testing.T) {
-	config := ProjectConfig{
-		Module: "github.com/test/my-app",
-	}
-
-	result := generateGoMod(config)
-	expected := "module github.com/test/my-app"
-	if !strings.Contains(result, expected) {
-		t.Fatalf("Expected %s in result, got: %s", expected, result)
-	}
-
-	if !strings.Contains(result, "go 1.24.0") {
-		t.Fatal("Expected Go version in go.mod")
-	}
-}
-
-func TestGenerateMainGo(t *testing.T) {
-	result := generateMainGo()
-	if !strings.Contains(result, "package main") {
-		t.Fatal("Expected package main")
-	}
-
-	if !strings.Contains(result, "webview") {
-		t.Fatal("Expected webview import")
-	}
-
-	if !strings.Contains(result, "func main()") {
-		t.Fatal("Expected main function")
-	}
-}
-
-func TestGenerateMakefile(t *testing.T) {
-	config := ProjectConfig{
-		Name: "test-app",
-	}
-
-	result := generateMakefile(config)
-	if !strings.Contains(result, "PROJECT_NAME := test-app") {
-		t.Fatal("Expected project name in Makefile")
-	}
-
-	if !strings.Contains(result, ".PHONY: help all") {
-		t.Fatal("Expected make targets")
-	}
-}
-
-func TestGenerateReadme(t *testing.T) {
-	config := ProjectConfig{
-		Name:        "Test App",
-		Description: "A test application",
-	}
-
-	result := generateReadme(config)
-	if !strings.Contains(result, "# Test App") {
-		t.Fatal("Expected title in README")
-	}
-
-	if !strings.Contains(result, "A test application") {
-		t.Fatal("Expected description in README")
-	}
-}
-
-func TestGenerateBackendGoMod(t *testing.T) {
-	result := generateBackendGoMod()
-	expected := "module backend"
-	if !strings.Contains(result, expected) {
-		t.Fatalf("Expected %s in result, got: %s", expected, result)
-	}
-
-	if !strings.Contains(result, "go 1.24") {
-		t.Fatal("Expected Go version")
-	}
-}
-
-func TestGenerateBackendMain(t *testing.T) {
-	result := generateBackendMain()
-	if !strings.Contains(result, "package main") {
-		t.Fatal("Expected package main")
-	}
-
-	if !strings.Contains(result, "gin.Default()") {
-		t.Fatal("Expected Gin router")
-	}
-}
-
-func TestGenerateConfig(t *testing.T) {
-	result := generateConfig()
-	if !strings.Contains(result, "package config") {
-		t.Fatal("Expected package config")
-	}
-
-	if !strings.Contains(result, "type Config struct") {
-		t.Fatal("Expected Config struct")
-	}
-}
-
-func TestGenerateRoutes(t *testing.T) {
-	result := generateRoutes()
-	if !strings.Contains(result, "package api") {
-		t.Fatal("Expected package api")
-	}
-
-	if !strings.Contains(result, "SetupRoutes") {
-		t.Fatal("Expected SetupRoutes function")
-	}
-}
-
-func TestGenerateHandlers(t *testing.T) {
-	result := generateHandlers()
-	if !strings.Contains(result, "package handlers") {
-		t.Fatal("Expected package handlers")
-	}
-
-	if !strings.Contains(result, "ListItems") {
-		t.Fatal("Expected ListItems function")
-	}
-}
-
-func TestGenerateCorsMiddleware(t *testing.T) {
-	result := generateCorsMiddleware()
-	if !strings.Contains(result, "package middleware") {
-		t.Fatal("Expected package middleware")
-	}
-
-	if !strings.Contains(result, "CORS()") {
-		t.Fatal("Expected CORS function")
-	}
-}
-
-func TestGenerateLoggerMiddleware(t *testing.T) {
-	result := generateLoggerMiddleware()
-	if !strings.Contains(result, "package middleware") {
-		t.Fatal("Expected package middleware")
-	}
-
-	if !strings.Contains(result, "Logger()") {
-		t.Fatal("Expected Logger function")
-	}
-}
-
-func TestGeneratePipelineModel(t *testing.T) {
-	result := generatePipelineModel()
-	if !strings.Contains(result, "package models") {
-		t.Fatal("Expected package models")
-	}
-
-	if !strings.Contains(result, "type Pipeline struct") {
-		t.Fatal("Expected Pipeline struct")
-	}
-}
-
-func TestGenerateProjectModel(t *testing.T) {
-	result := generateProjectModel()
-	if !strings.Contains(result, "package models") {
-		t.Fatal("Expected package models")
-	}
-
-	if !strings.Contains(result, "type Project struct") {
-		t.Fatal("Expected Project struct")
-	}
-}
-
-func TestGenerateUserModel(t *testing.T) {
-	result := generateUserModel()
-	if !strings.Contains(result, "package models") {
-		t.Fatal("Expected package models")
-	}
-
-	if !strings.Contains(result, "type User struct") {
-		t.Fatal("Expected User struct")
-	}
-}
-
-func TestGenerateModulesManager(t *testing.T) {
-	result := generateModulesManager()
-	if !strings.Contains(result, "package modules") {
-		t.Fatal("Expected package modules")
-	}
-
-	if !strings.Contains(result, "type Manager struct") {
-		t.Fatal("Expected Manager struct")
-	}
-}
-
-func TestGenerateBuiltinModules(t *testing.T) {
-	result := generateBuiltinModules()
-	if !strings.Contains(result, "package modules") {
-		t.Fatal("Expected package modules")
-	}
-
-	if !strings.Contains(result, "ExampleModule") {
-		t.Fatal("Expected ExampleModule")
-	}
-}
-
-func TestGenerateDatabase(t *testing.T) {
-	result := generateDatabase()
-	if !strings.Contains(result, "package storage") {
-		t.Fatal("Expected package storage")
-	}
-
-	if !strings.Contains(result, "type Database struct") {
-		t.Fatal("Expected Database struct")
-	}
-}
-
-func TestGenerateLogger(t *testing.T) {
-	result := generateLogger()
-	if !strings.Contains(result, "package utils") {
-		t.Fatal("Expected package utils")
-	}
-
-	if !strings.Contains(result, "Logger = log.New") {
-		t.Fatal("Expected Logger initialization")
-	}
-}
-
-func TestGeneratePackageJson(t *testing.T) {
-	config := ProjectConfig{
-		Name:        "test-app",
-		Description: "Test app",
-	}
-
-	result := generatePackageJson(config)
-	if !strings.Contains(result, `"name": "test-app"`) {
-		t.Fatal("Expected package name")
-	}
-
-	if !strings.Contains(result, `"description": "Test app"`) {
-		t.Fatal("Expected description")
-	}
-}
-
-func TestGenerateViteConfig(t *testing.T) {
-	config := ProjectConfig{
-		Port:        3000,
-		BackendPort: 9000,
-	}
-
-	result := generateViteConfig(config)
-	if !strings.Contains(result, "port: 3000") {
-		t.Fatal("Expected frontend port")
-	}
-
-	if !strings.Contains(result, "localhost:9000") {
-		t.Fatal("Expected backend port in proxy")
-	}
-}
-
-func TestGenerateTailwindConfig(t *testing.T) {
-	result := generateTailwindConfig()
-	if !strings.Contains(result, "brand:") {
-		t.Fatal("Expected brand colors")
-	}
-}
-
-func TestGeneratePostcssConfig(t *testing.T) {
-	result := generatePostcssConfig()
-	if !strings.Contains(result, "tailwindcss") {
-		t.Fatal("Expected tailwindcss plugin")
-	}
-}
-
-func TestGenerateIndexHtml(t *testing.T) {
-	config := ProjectConfig{
-		Name: "Test App",
-	}
-
-	result := generateIndexHtml(config)
-	if !strings.Contains(result, "<title>Test App</title>") {
-		t.Fatal("Expected title")
-	}
-}
-
-func TestGenerateMainTsx(t *testing.T) {
-	result := generateMainTsx()
-	if !strings.Contains(result, "ReactDOM.createRoot") {
-		t.Fatal("Expected React root creation")
-	}
-}
-
-func TestGenerateAppTsx(t *testing.T) {
-	config := ProjectConfig{
-		Name:        "Test App",
-		Description: "Test description",
-	}
-
-	result := generateAppTsx(config)
-	if !strings.Contains(result, "Test App") {
-		t.Fatal("Expected app name")
-	}
-
-	if !strings.Contains(result, "Test description") {
-		t.Fatal("Expected description")
-	}
-}
-
-func TestGenerateIndexCss(t *testing.T) {
-	result := generateIndexCss()
-	if !strings.Contains(result, "@tailwind") {
-		t.Fatal("Expected Tailwind directives")
-	}
-}
-
-func TestGenerateEslintrc(t *testing.T) {
-	result := generateEslintrc()
-	if !strings.Contains(result, "eslint:recommended") {
-		t.Fatal("Expected ESLint config")
-	}
-}
-
-func TestGeneratePrettierrc(t *testing.T) {
-	result := generatePrettierrc()
-	if !strings.Contains(result, `"semi": true`) {
-		t.Fatal("Expected Prettier config")
-	}
-}
-
-func TestGenerateGitignore(t *testing.T) {
-	result := generateGitignore()
-	if !strings.Contains(result, "node_modules/") {
-		t.Fatal("Expected node_modules in gitignore")
-	}
-}
-
-func TestGenerateGitattributes(t *testing.T) {
-	result := generateGitattributes()
-	if !strings.Contains(result, "* text=auto") {
-		t.Fatal("Expected gitattributes content")
-	}
-}
-
-func TestGenerateEnvExample(t *testing.T) {
-	config := ProjectConfig{
-		Name:        "test-app",
-		BackendPort: 9000,
-	}
-
-	result := generateEnvExample(config)
-	if !strings.Contains(result, "VITE_APP_NAME=test-app") {
-		t.Fatal("Expected app name in env")
-	}
-
-	if !strings.Contains(result, "PORT=9000") {
-		t.Fatal("Expected backend port in env")
-	}
-}
-
-func TestGenerateNetlifyToml(t *testing.T) {
-	config := ProjectConfig{
-		Name: "test-app",
-	}
-	result := generateNetlifyToml(config)
-	if !strings.Contains(result, "[build]") {
-		t.Fatal("Expected build section")
-	}
-}
-
-func TestGenerateNetlifyApiFunction(t *testing.T) {
-	result := generateNetlifyApiFunction()
-	if !strings.Contains(result, "exports.handler") {
-		t.Fatal("Expected Netlify function")
-	}
-}
-
 func TestRunInitDefaultName(t *testing.T) {
 	// Test with no args (should use default name)
 	tempDir, err := os.MkdirTemp("", "govite-test-*")
@@ -814,8 +475,9 @@ func TestGetModuleDestinationPath(t *testing.T) {
 	}
 
 	path = getModuleDestinationPath("test-module", NodeProject)
+	expected = filepath.Join("frontend", "modules", "test-module")
 	if path != expected {
-		t.Fatalf("Expected same path for Node.js module, got '%s'", path)
+		t.Fatalf("Expected '%s' for Node.js module, got '%s'", expected, path)
 	}
 
 	path = getModuleDestinationPath("test-module", Unknown)
@@ -0,0 +1,564 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	manifestFileName   = "govite-module.json"
+	backendGoModFile   = "backend/go.mod"
+	routesFile         = "backend/internal/api/routes.go"
+	migrationsDir      = "backend/internal/storage/migrations"
+	envExampleFile     = ".env.example"
+	frontendPagesDir   = "frontend/src/pages"
+	frontendCompsDir   = "frontend/src/components"
+	defaultConstructor = "New"
+)
+
+// ModuleManifest describes what a module contributes to a project. It is read
+// from govite-module.json at the root of the module.
+type ModuleManifest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Dependencies maps the names of other go-vite modules this module needs
+	// to the version ranges it accepts.
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	Go           *ManifestGo       `json:"go,omitempty"`
+	Routes       []ManifestRoute   `json:"routes,omitempty"`
+	Frontend     *ManifestFrontend `json:"frontend,omitempty"`
+	Env          []ManifestEnv     `json:"env,omitempty"`
+	Migrations   []string          `json:"migrations,omitempty"`
+	// Config lists module-local config files, as path.Match patterns
+	// relative to the module root, that update keeps from the installed copy.
+	Config      []string       `json:"config,omitempty"`
+	PostInstall []ManifestStep `json:"postInstall,omitempty"`
+	PreRemove   []ManifestStep `json:"preUninstall,omitempty"`
+}
+
+// ManifestGo names the Go package that implements the backend Module
+// interface and how it is registered with the module manager.
+type ManifestGo struct {
+	// Package is the package path relative to the module root ("" for the root).
+	Package string `json:"package,omitempty"`
+	// Register is the name passed to Manager.Register.
+	Register string `json:"register,omitempty"`
+	// Constructor is the exported function returning the Module, "New" by default.
+	Constructor string `json:"constructor,omitempty"`
+}
+
+// ManifestRoute is an HTTP route mounted on the /api/v1 group. Handler names
+// an exported gin.HandlerFunc in the module's Go package.
+type ManifestRoute struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler"`
+}
+
+// ManifestFrontend lists files, relative to the module root, that are copied
+// into frontend/src/pages/<module> and frontend/src/components/<module>.
+type ManifestFrontend struct {
+	Pages      []string `json:"pages,omitempty"`
+	Components []string `json:"components,omitempty"`
+}
+
+// ManifestEnv is an environment variable the module needs.
+type ManifestEnv struct {
+	Name        string `json:"name"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ManifestStep is a command run from the project root (or Dir, relative to
+// it) after install or before uninstall.
+type ManifestStep struct {
+	Command []string `json:"command"`
+	Dir     string   `json:"dir,omitempty"`
+}
+
+var (
+	goIdentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	envNamePattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+	routeMethods   = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true, "HEAD": true}
+)
+
+// loadModuleManifest reads govite-module.json from dir. It returns nil without
+// an error when the module doesn't ship a manifest.
+func loadModuleManifest(dir string) (*ModuleManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	manifest := &ModuleManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFileName, err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFileName, err)
+	}
+	return manifest, nil
+}
+
+// Validate rejects manifests that would write outside the project or emit
+// code that can't compile.
+func (m *ModuleManifest) Validate() error {
+	for name, constraint := range m.Dependencies {
+		if name == "" || name == m.Name {
+			return fmt.Errorf("dependency %q is not another module", name)
+		}
+		if err := validateRange(constraint); err != nil {
+			return fmt.Errorf("dependency %s: %w", name, err)
+		}
+	}
+	if m.Go != nil {
+		if m.Go.Package != "" && !isRelativeSubpath(m.Go.Package) {
+			return fmt.Errorf("go.package %q must be a relative path inside the module", m.Go.Package)
+		}
+		if m.Go.Constructor != "" && !goIdentPattern.MatchString(m.Go.Constructor) {
+			return fmt.Errorf("go.constructor %q is not a Go identifier", m.Go.Constructor)
+		}
+	}
+	if len(m.Routes) > 0 && m.Go == nil {
+		return fmt.Errorf("routes require a go section")
+	}
+	for _, route := range m.Routes {
+		if !routeMethods[strings.ToUpper(route.Method)] {
+			return fmt.Errorf("route %s %s: unsupported method", route.Method, route.Path)
+		}
+		if !strings.HasPrefix(route.Path, "/") || strings.ContainsAny(route.Path, "\"`\\ ") {
+			return fmt.Errorf("route path %q must start with / and contain no quotes or spaces", route.Path)
+		}
+		if !goIdentPattern.MatchString(route.Handler) {
+			return fmt.Errorf("route handler %q is not a Go identifier", route.Handler)
+		}
+	}
+	if m.Frontend != nil {
+		for _, file := range append(append([]string{}, m.Frontend.Pages...), m.Frontend.Components...) {
+			if !isRelativeSubpath(file) {
+				return fmt.Errorf("frontend file %q must be a relative path inside the module", file)
+			}
+		}
+	}
+	for _, env := range m.Env {
+		if !envNamePattern.MatchString(env.Name) {
+			return fmt.Errorf("env var %q must be upper case letters, digits and underscores", env.Name)
+		}
+		if strings.ContainsAny(env.Default, "\r\n") {
+			return fmt.Errorf("env var %s: default must be a single line", env.Name)
+		}
+		if strings.ContainsAny(env.Description, "\r\n") {
+			return fmt.Errorf("env var %s: description must be a single line", env.Name)
+		}
+	}
+	for _, migration := range m.Migrations {
+		if !isRelativeSubpath(migration) {
+			return fmt.Errorf("migration %q must be a relative path inside the module", migration)
+		}
+	}
+	for _, pattern := range m.Config {
+		if _, err := path.Match(pattern, ""); err != nil || !isRelativeSubpath(pattern) {
+			return fmt.Errorf("config pattern %q must be a relative path pattern inside the module", pattern)
+		}
+	}
+	for _, step := range append(append([]ManifestStep{}, m.PostInstall...), m.PreRemove...) {
+		if len(step.Command) == 0 {
+			return fmt.Errorf("install steps need a command")
+		}
+		if step.Dir != "" && !isRelativeSubpath(step.Dir) {
+			return fmt.Errorf("step dir %q must be a relative path inside the project", step.Dir)
+		}
+	}
+	return nil
+}
+
+func isRelativeSubpath(p string) bool {
+	if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "/") {
+		return false
+	}
+	clean := filepath.Clean(filepath.FromSlash(p))
+	return clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// goAlias turns a module name into the identifier used to import its Go
+// package, e.g. "github.com/org/csv-export" -> "csvexport".
+func goAlias(moduleName string) string {
+	base := moduleName[strings.LastIndex(moduleName, "/")+1:]
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	alias := b.String()
+	if alias == "" || (alias[0] >= '0' && alias[0] <= '9') {
+		alias = "mod" + alias
+	}
+	return alias
+}
+
+// moduleBinding holds the names derived from a manifest for a Go module.
+type moduleBinding struct {
+	importPath string
+	alias      string
+	register   string
+	ctor       string
+}
+
+func (m *ModuleManifest) goBinding(moduleName string) moduleBinding {
+	b := moduleBinding{
+		importPath: moduleName,
+		alias:      goAlias(moduleName),
+		register:   moduleName,
+		ctor:       defaultConstructor,
+	}
+	if m.Go.Package != "" {
+		b.importPath = moduleName + "/" + strings.Trim(filepath.ToSlash(m.Go.Package), "/")
+		b.alias = goAlias(b.importPath)
+	}
+	if m.Go.Register != "" {
+		b.register = m.Go.Register
+	}
+	if m.Go.Constructor != "" {
+		b.ctor = m.Go.Constructor
+	}
+	return b
+}
+
+func (b moduleBinding) registrationLine() string {
+	return fmt.Sprintf("\tmanager.Register(%q, %s.%s())", b.register, b.alias, b.ctor)
+}
+
+func routeLine(alias string, route ManifestRoute) string {
+	return fmt.Sprintf("\tv1.%s(%q, %s.%s)", strings.ToUpper(route.Method), route.Path, alias, route.Handler)
+}
+
+// applyModuleManifest wires everything the manifest declares into the
+// project rooted at the current directory. modulePath is where the module
+// was copied to.
+func applyModuleManifest(manifest *ModuleManifest, moduleName string, moduleType ProjectType, modulePath string) error {
+	if manifest.Go != nil {
+		if moduleType != GoProject {
+			return fmt.Errorf("go section requires a Go module")
+		}
+		if err := applyManifestGo(manifest, moduleName, modulePath); err != nil {
+			return err
+		}
+	}
+
+	if manifest.Frontend != nil {
+		dirName := goAlias(moduleName)
+		if err := copyManifestFiles(modulePath, manifest.Frontend.Pages, filepath.Join(frontendPagesDir, dirName)); err != nil {
+			return fmt.Errorf("failed to install pages: %w", err)
+		}
+		if err := copyManifestFiles(modulePath, manifest.Frontend.Components, filepath.Join(frontendCompsDir, dirName)); err != nil {
+			return fmt.Errorf("failed to install components: %w", err)
+		}
+	}
+
+	if len(manifest.Env) > 0 {
+		if err := addEnvBlock(envExampleFile, moduleName, manifest.Env); err != nil {
+			return fmt.Errorf("failed to update %s: %w", envExampleFile, err)
+		}
+		if _, err := os.Stat(".env"); err == nil {
+			if err := addEnvBlock(".env", moduleName, manifest.Env); err != nil {
+				return fmt.Errorf("failed to update .env: %w", err)
+			}
+		}
+		for _, env := range manifest.Env {
+			if env.Required && env.Default == "" && os.Getenv(env.Name) == "" {
+				fmt.Printf("⚠️  %s requires %s to be set\n", moduleName, env.Name)
+			}
+		}
+	}
+
+	if len(manifest.Migrations) > 0 {
+		if err := copyManifestFiles(modulePath, manifest.Migrations, filepath.Join(migrationsDir, goAlias(moduleName))); err != nil {
+			return fmt.Errorf("failed to install migrations: %w", err)
+		}
+	}
+
+	for _, step := range manifest.PostInstall {
+		if err := runManifestStep(step); err != nil {
+			return fmt.Errorf("post-install step %q failed: %w", strings.Join(step.Command, " "), err)
+		}
+	}
+	return nil
+}
+
+// unapplyModuleManifest reverses applyModuleManifest.
+func unapplyModuleManifest(manifest *ModuleManifest, moduleName string, moduleType ProjectType, modulePath string) error {
+	for _, step := range manifest.PreRemove {
+		if err := runManifestStep(step); err != nil {
+			return fmt.Errorf("pre-uninstall step %q failed: %w", strings.Join(step.Command, " "), err)
+		}
+	}
+
+	if manifest.Go != nil && moduleType == GoProject {
+		if err := unapplyManifestGo(manifest, moduleName); err != nil {
+			return err
+		}
+	}
+
+	dirName := goAlias(moduleName)
+	for _, dir := range []string{
+		filepath.Join(frontendPagesDir, dirName),
+		filepath.Join(frontendCompsDir, dirName),
+		filepath.Join(migrationsDir, dirName),
+	} {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{envExampleFile, ".env"} {
+		if err := removeEnvBlock(path, moduleName); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func applyManifestGo(manifest *ModuleManifest, moduleName, modulePath string) error {
+	binding := manifest.goBinding(moduleName)
+
+	rel, err := filepath.Rel("backend", modulePath)
+	if err != nil {
+		return err
+	}
+	if err := runGoModEdit("-require="+moduleName+"@v0.0.0", "-replace="+moduleName+"=./"+filepath.ToSlash(rel)); err != nil {
+		return fmt.Errorf("failed to update %s: %w", backendGoModFile, err)
+	}
+
+	if err := addGoImport(builtinModulesFile, binding.alias, binding.importPath); err != nil {
+		return err
+	}
+	if err := addLineToFunc(builtinModulesFile, "LoadBuiltinModules", binding.registrationLine()); err != nil {
+		return err
+	}
+	fmt.Printf("Registered backend module '%s' (%s.%s)\n", binding.register, binding.alias, binding.ctor)
+
+	if len(manifest.Routes) > 0 {
+		if err := addGoImport(routesFile, binding.alias, binding.importPath); err != nil {
+			return err
+		}
+		for _, route := range manifest.Routes {
+			if err := addLineToFunc(routesFile, "SetupRoutes", routeLine(binding.alias, route)); err != nil {
+				return err
+			}
+			fmt.Printf("Mounted %s /api/v1%s\n", strings.ToUpper(route.Method), route.Path)
+		}
+	}
+	return nil
+}
+
+func unapplyManifestGo(manifest *ModuleManifest, moduleName string) error {
+	binding := manifest.goBinding(moduleName)
+
+	for _, route := range manifest.Routes {
+		if err := removeLinesWithPrefix(routesFile, strings.TrimSpace(routeLine(binding.alias, route))); err != nil {
+			return err
+		}
+	}
+	if err := removeGoImport(routesFile, binding.alias, binding.importPath); err != nil {
+		return err
+	}
+
+	if err := removeLinesWithPrefix(builtinModulesFile, strings.TrimSpace(binding.registrationLine())); err != nil {
+		return err
+	}
+	if err := removeGoImport(builtinModulesFile, binding.alias, binding.importPath); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(backendGoModFile); err == nil {
+		if err := runGoModEdit("-dropreplace="+moduleName, "-droprequire="+moduleName); err != nil {
+			return fmt.Errorf("failed to update %s: %w", backendGoModFile, err)
+		}
+	}
+	return nil
+}
+
+func runGoModEdit(args ...string) error {
+	cmd := exec.Command("go", append([]string{"mod", "edit"}, args...)...)
+	cmd.Dir = "backend"
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// copyManifestFiles copies files listed in a manifest from the module into
+// destDir, keeping only their base names.
+func copyManifestFiles(modulePath string, files []string, destDir string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		src := filepath.Join(modulePath, filepath.FromSlash(file))
+		if err := copyFile(src, filepath.Join(destDir, filepath.Base(src))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func envBlockMarkers(moduleName string) (string, string) {
+	return "# >>> go-vite module " + moduleName, "# <<< go-vite module " + moduleName
+}
+
+// addEnvBlock appends the module's variables to an env file inside a marked
+// block. Variables already defined elsewhere in the file are left alone.
+func addEnvBlock(path, moduleName string, vars []ManifestEnv) error {
+	if err := removeEnvBlock(path, moduleName); err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	defined := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			defined[key] = true
+		}
+	}
+
+	begin, end := envBlockMarkers(moduleName)
+	var b strings.Builder
+	b.WriteString(string(content))
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n" + begin + "\n")
+	for _, env := range vars {
+		if defined[env.Name] {
+			continue
+		}
+		if env.Description != "" {
+			b.WriteString("# " + env.Description + "\n")
+		}
+		b.WriteString(env.Name + "=" + env.Default + "\n")
+	}
+	b.WriteString(end + "\n")
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// envBlock returns the offsets of the module's block in an env file, from
+// the start of its first line to the end of its last, or -1 if there is none.
+func envBlock(src, moduleName string) (int, int, error) {
+	begin, end := envBlockMarkers(moduleName)
+	start := strings.Index(src, begin+"\n")
+	if start == -1 {
+		return -1, -1, nil
+	}
+	stop := strings.Index(src[start:], end+"\n")
+	if stop == -1 {
+		return -1, -1, fmt.Errorf("unterminated go-vite block for %s", moduleName)
+	}
+	return start, stop + start + len(end) + 1, nil
+}
+
+// removeEnvBlock deletes the block written by addEnvBlock.
+func removeEnvBlock(path, moduleName string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	src := string(content)
+	start, stop, err := envBlock(src, moduleName)
+	if start == -1 {
+		return err
+	}
+	if start > 0 && src[start-1] == '\n' && strings.HasSuffix(src[:start-1], "\n") {
+		start--
+	}
+	return os.WriteFile(path, []byte(src[:start]+src[stop:]), 0644)
+}
+
+// envBlockValues returns the variables set in the module's block of an env
+// file, as the user last edited them.
+func envBlockValues(path, moduleName string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	src := string(content)
+	start, stop, err := envBlock(src, moduleName)
+	if start == -1 {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(src[start:stop], "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// setEnvBlockValues puts values back into the module's block of an env file.
+// Variables the block no longer has are dropped.
+func setEnvBlockValues(path, moduleName string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	src := string(content)
+	start, stop, err := envBlock(src, moduleName)
+	if start == -1 {
+		return err
+	}
+	lines := strings.Split(src[start:stop], "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			if value, kept := values[key]; kept {
+				lines[i] = key + "=" + value
+			}
+		}
+	}
+	return os.WriteFile(path, []byte(src[:start]+strings.Join(lines, "\n")+src[stop:]), 0644)
+}
+
+func runManifestStep(step ManifestStep) error {
+	fmt.Printf("Running: %s\n", strings.Join(step.Command, " "))
+	cmd := exec.Command(step.Command[0], step.Command[1:]...)
+	if step.Dir != "" {
+		cmd.Dir = step.Dir
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
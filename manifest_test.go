@@ -0,0 +1,203 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestGoModule creates a Go module with a manifest under dir.
+func writeTestGoModule(t *testing.T, dir string) {
+	files := map[string]string{
+		"go.mod":            "module github.com/test/csv-export\n\ngo 1.24\n",
+		"module.go":         "package csvexport\n",
+		"web/CsvPage.tsx":   "export default function CsvPage() { return null }\n",
+		"web/CsvButton.tsx": "export function CsvButton() { return null }\n",
+		"sql/001_init.sql":  "CREATE TABLE exports (id TEXT);\n",
+		manifestFileName: `{
+  "name": "csv-export",
+  "version": "1.2.0",
+  "go": {"register": "csv-export"},
+  "routes": [{"method": "get", "path": "/csv/export", "handler": "ExportHandler"}],
+  "frontend": {"pages": ["web/CsvPage.tsx"], "components": ["web/CsvButton.tsx"]},
+  "env": [{"name": "CSV_DELIMITER", "default": ",", "description": "Field separator"}],
+  "migrations": ["sql/001_init.sql"]
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadModuleManifestMissing(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "govite-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manifest, err := loadModuleManifest(tempDir)
+	if err != nil || manifest != nil {
+		t.Fatalf("Expected no manifest and no error, got %v, %v", manifest, err)
+	}
+}
+
+func TestModuleManifestValidate(t *testing.T) {
+	invalid := []ModuleManifest{
+		{Routes: []ManifestRoute{{Method: "GET", Path: "/x", Handler: "H"}}},
+		{Go: &ManifestGo{}, Routes: []ManifestRoute{{Method: "FETCH", Path: "/x", Handler: "H"}}},
+		{Go: &ManifestGo{}, Routes: []ManifestRoute{{Method: "GET", Path: `/x")`, Handler: "H"}}},
+		{Go: &ManifestGo{Constructor: "New()"}},
+		{Go: &ManifestGo{Package: "../escape"}},
+		{Frontend: &ManifestFrontend{Pages: []string{"/etc/passwd"}}},
+		{Env: []ManifestEnv{{Name: "lower"}}},
+		{Env: []ManifestEnv{{Name: "OK", Default: "a\nB=c"}}},
+		{Env: []ManifestEnv{{Name: "OK", Description: "key\nADMIN=1"}}},
+		{Env: []ManifestEnv{{Name: "OK", Description: "key\rADMIN=1"}}},
+		{Migrations: []string{"../../x.sql"}},
+		{PostInstall: []ManifestStep{{}}},
+		{Name: "reports", Dependencies: map[string]string{"reports": "^1"}},
+		{Dependencies: map[string]string{"storage": ">=x"}},
+	}
+	for i, manifest := range invalid {
+		if err := manifest.Validate(); err == nil {
+			t.Fatalf("Expected manifest %d to be rejected", i)
+		}
+	}
+
+	valid := ModuleManifest{
+		Go:     &ManifestGo{Package: "pkg/api"},
+		Routes: []ManifestRoute{{Method: "post", Path: "/reports/:id", Handler: "Create"}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected manifest to be valid, got %v", err)
+	}
+}
+
+func TestGoAlias(t *testing.T) {
+	cases := map[string]string{
+		"github.com/org/csv-export": "csvexport",
+		"storage_sqlite":            "storagesqlite",
+		"3d-viewer":                 "mod3dviewer",
+	}
+	for input, expected := range cases {
+		if got := goAlias(input); got != expected {
+			t.Fatalf("goAlias(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestApplyAndUnapplyModuleManifest(t *testing.T) {
+	tempDir, cleanup := newTestProject(t)
+	defer cleanup()
+
+	modulePath := getModuleDestinationPath("github.com/test/csv-export", GoProject)
+	writeTestGoModule(t, modulePath)
+
+	manifest, err := loadModuleManifest(modulePath)
+	if err != nil {
+		t.Fatalf("loadModuleManifest failed: %v", err)
+	}
+
+	originalBuiltin, _ := os.ReadFile(builtinModulesFile)
+	originalRoutes, _ := os.ReadFile(routesFile)
+	originalEnv, _ := os.ReadFile(envExampleFile)
+
+	if err := applyModuleManifest(manifest, "github.com/test/csv-export", GoProject, modulePath); err != nil {
+		t.Fatalf("applyModuleManifest failed: %v", err)
+	}
+
+	builtin, _ := os.ReadFile(builtinModulesFile)
+	if !strings.Contains(string(builtin), `csvexport "github.com/test/csv-export"`) {
+		t.Fatalf("Expected import in builtin.go, got: %s", builtin)
+	}
+	if !strings.Contains(string(builtin), `manager.Register("csv-export", csvexport.New())`) {
+		t.Fatalf("Expected registration in builtin.go, got: %s", builtin)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "builtin.go", builtin, 0); err != nil {
+		t.Fatalf("builtin.go no longer parses: %v", err)
+	}
+
+	routes, _ := os.ReadFile(routesFile)
+	if !strings.Contains(string(routes), `v1.GET("/csv/export", csvexport.ExportHandler)`) {
+		t.Fatalf("Expected route in routes.go, got: %s", routes)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "routes.go", routes, 0); err != nil {
+		t.Fatalf("routes.go no longer parses: %v", err)
+	}
+
+	goMod, _ := os.ReadFile(backendGoModFile)
+	if !strings.Contains(string(goMod), "github.com/test/csv-export => ./internal/modules/github.com/test/csv-export") {
+		t.Fatalf("Expected replace directive in backend/go.mod, got: %s", goMod)
+	}
+
+	for _, path := range []string{
+		filepath.Join(frontendPagesDir, "csvexport", "CsvPage.tsx"),
+		filepath.Join(frontendCompsDir, "csvexport", "CsvButton.tsx"),
+		filepath.Join(migrationsDir, "csvexport", "001_init.sql"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("Expected %s to be installed", path)
+		}
+	}
+
+	env, _ := os.ReadFile(envExampleFile)
+	if !strings.Contains(string(env), "CSV_DELIMITER=,") {
+		t.Fatalf("Expected env var in .env.example, got: %s", env)
+	}
+
+	if err := unapplyModuleManifest(manifest, "github.com/test/csv-export", GoProject, modulePath); err != nil {
+		t.Fatalf("unapplyModuleManifest failed: %v", err)
+	}
+
+	if builtin, _ := os.ReadFile(builtinModulesFile); string(builtin) != string(originalBuiltin) {
+		t.Fatalf("Expected builtin.go to be restored, got: %s", builtin)
+	}
+	if routes, _ := os.ReadFile(routesFile); string(routes) != string(originalRoutes) {
+		t.Fatalf("Expected routes.go to be restored, got: %s", routes)
+	}
+	if env, _ := os.ReadFile(envExampleFile); string(env) != string(originalEnv) {
+		t.Fatalf("Expected .env.example to be restored, got: %q", env)
+	}
+	if goMod, _ := os.ReadFile(filepath.Join(tempDir, backendGoModFile)); strings.Contains(string(goMod), "csv-export") {
+		t.Fatalf("Expected module to be dropped from backend/go.mod, got: %s", goMod)
+	}
+	if _, err := os.Stat(filepath.Join(frontendPagesDir, "csvexport")); !os.IsNotExist(err) {
+		t.Fatal("Expected pages to be removed")
+	}
+}
+
+func TestEnvBlockKeepsExistingVariables(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "govite-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, ".env")
+	os.WriteFile(path, []byte("PORT=9000\n"), 0644)
+
+	vars := []ManifestEnv{{Name: "PORT", Default: "8080"}, {Name: "API_KEY"}}
+	if err := addEnvBlock(path, "mod", vars); err != nil {
+		t.Fatalf("addEnvBlock failed: %v", err)
+	}
+	// Applying twice must not duplicate the block
+	if err := addEnvBlock(path, "mod", vars); err != nil {
+		t.Fatalf("addEnvBlock failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if strings.Count(string(content), "API_KEY=") != 1 {
+		t.Fatalf("Expected API_KEY once, got: %s", content)
+	}
+	if strings.Contains(string(content), "PORT=8080") {
+		t.Fatal("Existing variables must not be overridden")
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"govite/generator"
+)
+
+const (
+	frontendDir        = "frontend"
+	frontendModulesDir = "frontend/modules"
+	builtinModulesFile = "backend/internal/modules/builtin.go"
+	nodeRunnerFile     = "backend/internal/modules/node.go"
+	packageJSONFile    = "frontend/package.json"
+	viteConfigFile     = "frontend/vite.config.js"
+)
+
+// nodePackage is the subset of package.json that go-vite cares about.
+type nodePackage struct {
+	Name    string             `json:"name"`
+	Version string             `json:"version"`
+	Main    string             `json:"main"`
+	Govite  *nodeGoviteSection `json:"govite"`
+}
+
+// nodeGoviteSection is the optional "govite" block of a Node module's
+// package.json. A module with role "backend" is registered as a runnable
+// backend module and is invoked through the NodeModule contract.
+type nodeGoviteSection struct {
+	Role  string `json:"role"`
+	Entry string `json:"entry"`
+}
+
+func readNodePackage(dir string) (*nodePackage, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	pkg := &nodePackage{}
+	if err := json.Unmarshal(content, pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+	return pkg, nil
+}
+
+// backendEntry returns the script a backend-role module should be run with,
+// or "" when the module is frontend-only.
+func (p *nodePackage) backendEntry() string {
+	if p.Govite == nil || p.Govite.Role != "backend" {
+		return ""
+	}
+	if p.Govite.Entry != "" {
+		return p.Govite.Entry
+	}
+	if p.Main != "" {
+		return p.Main
+	}
+	return "index.js"
+}
+
+// jsonObject is a JSON object that keeps its keys in source order so that
+// files such as package.json can be edited without reshuffling them.
+type jsonObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]json.RawMessage)}
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected JSON object")
+	}
+	o.keys = nil
+	o.values = make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if _, exists := o.values[key]; !exists {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = value
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o *jsonObject) Get(key string) (json.RawMessage, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o *jsonObject) Set(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = raw
+	return nil
+}
+
+func (o *jsonObject) Delete(key string) {
+	if _, exists := o.values[key]; !exists {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func readJSONObject(path string) (*jsonObject, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	obj := newJSONObject()
+	if err := json.Unmarshal(content, obj); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return obj, nil
+}
+
+func writeJSONObject(path string, obj *jsonObject) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(obj); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// editPackageDependency adds (or, with an empty spec, removes) a dependency in
+// frontend/package.json while preserving the order of the existing keys.
+func editPackageDependency(name, spec string) error {
+	pkg, err := readJSONObject(packageJSONFile)
+	if err != nil {
+		return err
+	}
+
+	deps := newJSONObject()
+	if raw, ok := pkg.Get("dependencies"); ok {
+		if err := json.Unmarshal(raw, deps); err != nil {
+			return fmt.Errorf("invalid dependencies in %s: %w", packageJSONFile, err)
+		}
+	}
+
+	if spec == "" {
+		deps.Delete(name)
+	} else if err := deps.Set(name, spec); err != nil {
+		return err
+	}
+
+	if err := pkg.Set("dependencies", deps); err != nil {
+		return err
+	}
+	return writeJSONObject(packageJSONFile, pkg)
+}
+
+// frontendFileSpec returns the file: dependency spec for a module installed
+// at modulePath, relative to the frontend directory.
+func frontendFileSpec(modulePath string) (string, error) {
+	rel, err := filepath.Rel(frontendDir, modulePath)
+	if err != nil {
+		return "", err
+	}
+	return "file:./" + filepath.ToSlash(rel), nil
+}
+
+// viteAliasPattern matches the import aliases go-vite writes into
+// vite.config.js, which go there as single-quoted JavaScript strings.
+var viteAliasPattern = regexp.MustCompile(`^[@~#$]?[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+func checkViteAlias(alias string) error {
+	if !viteAliasPattern.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: use letters, digits, '.', '_', '-' and '/', optionally after '@', '~', '#' or '$'", alias)
+	}
+	return nil
+}
+
+// addViteAlias adds an import alias pointing at the installed module to the
+// resolve.alias block of frontend/vite.config.js.
+func addViteAlias(alias, modulePath string) error {
+	if err := checkViteAlias(alias); err != nil {
+		return err
+	}
+	content, err := os.ReadFile(viteConfigFile)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(frontendDir, modulePath)
+	if err != nil {
+		return err
+	}
+	if strings.ContainsAny(filepath.ToSlash(rel), "'\\\r\n") {
+		return fmt.Errorf("module path %q cannot be written to %s", rel, viteConfigFile)
+	}
+	entry := fmt.Sprintf("      '%s': path.resolve(__dirname, './%s'),", alias, filepath.ToSlash(rel))
+
+	src := string(content)
+	if strings.Contains(src, entry) {
+		return nil
+	}
+	marker := "alias: {\n"
+	idx := strings.Index(src, marker)
+	if idx == -1 {
+		return fmt.Errorf("no resolve.alias block found in %s", viteConfigFile)
+	}
+	idx += len(marker)
+	src = src[:idx] + entry + "\n" + src[idx:]
+	return os.WriteFile(viteConfigFile, []byte(src), 0644)
+}
+
+// removeViteAlias removes an alias previously added by addViteAlias.
+func removeViteAlias(alias string) error {
+	return removeLinesWithPrefix(viteConfigFile, fmt.Sprintf("'%s': path.resolve(", alias))
+}
+
+// installNodeFrontendModule wires a copied Node module into the frontend as a
+// file: dependency and, for backend-role modules, registers it with the
+// backend module manager.
+func installNodeFrontendModule(moduleName, modulePath string, opts installOptions) error {
+	spec, err := frontendFileSpec(modulePath)
+	if err != nil {
+		return err
+	}
+	if err := editPackageDependency(moduleName, spec); err != nil {
+		return fmt.Errorf("failed to update %s: %w", packageJSONFile, err)
+	}
+	fmt.Printf("Added %s@%s to %s\n", moduleName, spec, packageJSONFile)
+
+	if opts.Alias != "" {
+		if err := addViteAlias(opts.Alias, modulePath); err != nil {
+			return fmt.Errorf("failed to add vite alias: %w", err)
+		}
+		fmt.Printf("Added vite alias '%s' -> %s\n", opts.Alias, modulePath)
+	}
+
+	pkg, err := readNodePackage(modulePath)
+	if err != nil {
+		return err
+	}
+	if entry := pkg.backendEntry(); entry != "" {
+		if err := registerNodeBackendModule(moduleName, modulePath, entry); err != nil {
+			return fmt.Errorf("failed to register backend module: %w", err)
+		}
+	}
+	return nil
+}
+
+// nodeRegistrationLine is the line added to LoadBuiltinModules for a
+// backend-role Node module.
+func nodeRegistrationLine(moduleName, modulePath, entry string) string {
+	return fmt.Sprintf("\tmanager.Register(%q, NewNodeModule(%q, %q, %q))",
+		moduleName, moduleName, filepath.ToSlash(modulePath), entry)
+}
+
+func registerNodeBackendModule(moduleName, modulePath, entry string) error {
+	if _, err := os.Stat(nodeRunnerFile); os.IsNotExist(err) {
+		if err := os.WriteFile(nodeRunnerFile, []byte(generator.NodeModuleRunner()), 0644); err != nil {
+			return err
+		}
+	}
+	line := nodeRegistrationLine(moduleName, modulePath, entry)
+	if err := addLineToFunc(builtinModulesFile, "LoadBuiltinModules", line); err != nil {
+		return err
+	}
+	fmt.Printf("Registered backend module '%s' (node %s)\n", moduleName, entry)
+	return nil
+}
+
+// removeBuiltinRegistration deletes every registration of moduleName from
+// LoadBuiltinModules.
+func removeBuiltinRegistration(moduleName string) error {
+	return removeLinesWithPrefix(builtinModulesFile, fmt.Sprintf("manager.Register(%q,", moduleName))
+}
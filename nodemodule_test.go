@@ -0,0 +1,198 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestProject generates a project in a temporary directory and changes
+// into it. The returned function restores the working directory.
+func newTestProject(t *testing.T) (string, func()) {
+	tempDir, err := os.MkdirTemp("", "govite-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	config := ProjectConfig{
+		Name:        "test-app",
+		Module:      "test-app",
+		Description: "Test application",
+		Port:        5173,
+		BackendPort: 8080,
+	}
+	if err := createProjectStructure(tempDir, config); err != nil {
+		os.RemoveAll(tempDir)
+		t.Fatalf("createProjectStructure failed: %v", err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tempDir)
+	return tempDir, func() {
+		os.Chdir(oldWd)
+		os.RemoveAll(tempDir)
+	}
+}
+
+func TestJSONObjectPreservesKeyOrder(t *testing.T) {
+	obj := newJSONObject()
+	input := `{"name": "app", "version": "1.0.0", "scripts": {"build": "a && b"}}`
+	if err := obj.UnmarshalJSON([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	obj.Set("description", "added")
+	obj.Delete("version")
+
+	out, err := obj.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	expected := `{"name":"app","scripts":{"build": "a && b"},"description":"added"}`
+	if string(out) != expected {
+		t.Fatalf("Expected %s, got %s", expected, out)
+	}
+}
+
+func TestEditPackageDependency(t *testing.T) {
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	if err := editPackageDependency("@org/csv", "file:./modules/@org/csv"); err != nil {
+		t.Fatalf("editPackageDependency failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(packageJSONFile)
+	if !strings.Contains(string(content), `"@org/csv": "file:./modules/@org/csv"`) {
+		t.Fatalf("Expected file: dependency in package.json, got: %s", content)
+	}
+
+	// Existing keys must keep their position
+	if strings.Index(string(content), `"name"`) > strings.Index(string(content), `"dependencies"`) {
+		t.Fatal("Expected package.json key order to be preserved")
+	}
+
+	if err := editPackageDependency("@org/csv", ""); err != nil {
+		t.Fatalf("editPackageDependency removal failed: %v", err)
+	}
+
+	content, _ = os.ReadFile(packageJSONFile)
+	if strings.Contains(string(content), "@org/csv") {
+		t.Fatal("Expected dependency to be removed")
+	}
+}
+
+func TestViteAlias(t *testing.T) {
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	modulePath := filepath.Join(frontendModulesDir, "csv")
+	if err := addViteAlias("@csv", modulePath); err != nil {
+		t.Fatalf("addViteAlias failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(viteConfigFile)
+	if !strings.Contains(string(content), `'@csv': path.resolve(__dirname, './modules/csv'),`) {
+		t.Fatalf("Expected alias in vite config, got: %s", content)
+	}
+
+	if err := removeViteAlias("@csv"); err != nil {
+		t.Fatalf("removeViteAlias failed: %v", err)
+	}
+
+	content, _ = os.ReadFile(viteConfigFile)
+	if strings.Contains(string(content), "@csv") {
+		t.Fatal("Expected alias to be removed")
+	}
+
+	if !strings.Contains(string(content), `'@': path.resolve(__dirname, './src'),`) {
+		t.Fatal("Expected default alias to be kept")
+	}
+}
+
+func TestViteAliasRejectsCode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+	before, _ := os.ReadFile(viteConfigFile)
+
+	for _, alias := range []string{"x': require('child_process').execSync('id'), 'y", "@a\nb", "@", ""} {
+		if err := addViteAlias(alias, filepath.Join(frontendModulesDir, "csv")); err == nil {
+			t.Errorf("Expected alias %q to be rejected", alias)
+		}
+	}
+	for _, alias := range []string{"@csv", "~lib", "#shared/ui", "$util", "csv-export"} {
+		if err := checkViteAlias(alias); err != nil {
+			t.Errorf("Expected alias %q to be accepted, got %v", alias, err)
+		}
+	}
+	if after, _ := os.ReadFile(viteConfigFile); string(after) != string(before) {
+		t.Fatalf("Expected %s to be unchanged, got:\n%s", viteConfigFile, after)
+	}
+
+	// install-local refuses the alias before copying the module
+	source := t.TempDir()
+	writeTestNodeModule(t, source, "widgets", "1.0.0")
+	if err := installLocal(source, installOptions{Alias: "x'+y"}); err == nil || !strings.Contains(err.Error(), "invalid alias") {
+		t.Fatalf("Expected the alias to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(frontendModulesDir, "widgets")); !os.IsNotExist(err) {
+		t.Fatal("Expected the module not to be copied")
+	}
+}
+
+func TestInstallNodeFrontendModuleBackendRole(t *testing.T) {
+	tempDir, cleanup := newTestProject(t)
+	defer cleanup()
+
+	modulePath := filepath.Join(frontendModulesDir, "csv-export")
+	os.MkdirAll(modulePath, 0755)
+	os.WriteFile(filepath.Join(modulePath, "package.json"), []byte(`{
+  "name": "csv-export",
+  "main": "lib.js",
+  "govite": {"role": "backend", "entry": "server.js"}
+}`), 0644)
+
+	if err := installNodeFrontendModule("csv-export", modulePath, installOptions{}); err != nil {
+		t.Fatalf("installNodeFrontendModule failed: %v", err)
+	}
+
+	builtin, _ := os.ReadFile(filepath.Join(tempDir, builtinModulesFile))
+	expected := `manager.Register("csv-export", NewNodeModule("csv-export", "frontend/modules/csv-export", "server.js"))`
+	if !strings.Contains(string(builtin), expected) {
+		t.Fatalf("Expected registration in builtin.go, got: %s", builtin)
+	}
+
+	if err := removeBuiltinRegistration("csv-export"); err != nil {
+		t.Fatalf("removeBuiltinRegistration failed: %v", err)
+	}
+
+	builtin, _ = os.ReadFile(filepath.Join(tempDir, builtinModulesFile))
+	if strings.Contains(string(builtin), "csv-export") {
+		t.Fatal("Expected registration to be removed")
+	}
+
+	if !strings.Contains(string(builtin), `manager.Register("example", &ExampleModule{})`) {
+		t.Fatal("Expected builtin registrations to be kept")
+	}
+}
+
+func TestInstallNodeFrontendModuleFrontendOnly(t *testing.T) {
+	tempDir, cleanup := newTestProject(t)
+	defer cleanup()
+
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+	os.MkdirAll(modulePath, 0755)
+	os.WriteFile(filepath.Join(modulePath, "package.json"), []byte(`{"name": "widgets"}`), 0644)
+
+	if err := installNodeFrontendModule("widgets", modulePath, installOptions{}); err != nil {
+		t.Fatalf("installNodeFrontendModule failed: %v", err)
+	}
+
+	builtin, _ := os.ReadFile(filepath.Join(tempDir, builtinModulesFile))
+	if strings.Contains(string(builtin), "widgets") {
+		t.Fatal("Frontend-only module must not be registered with the backend")
+	}
+}
@@ -0,0 +1,651 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	registryIndexFile = "index.json"
+	registryEnvVar    = "GOVITE_REGISTRY"
+)
+
+// RegistryIndex is the index.json at the root of a module registry. A
+// registry is either a directory (local or on a shared drive) or a static
+// HTTP location serving the same layout:
+//
+//	index.json
+//	<name>/<version>.tar.gz
+type RegistryIndex struct {
+	Modules []RegistryModule `json:"modules"`
+}
+
+// RegistryModule is one published module and all its versions.
+type RegistryModule struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Versions    []RegistryVersion `json:"versions"`
+}
+
+// RegistryVersion points at the archive of one published version. Archive is
+// relative to the registry root.
+type RegistryVersion struct {
+	Version string `json:"version"`
+	Archive string `json:"archive"`
+	SHA256  string `json:"sha256"`
+}
+
+var registryNamePattern = regexp.MustCompile(`^@?[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+func validRegistryName(name string) bool {
+	return registryNamePattern.MatchString(name) && isRelativeSubpath(strings.TrimPrefix(name, "@"))
+}
+
+func (idx *RegistryIndex) find(name string) *RegistryModule {
+	for i := range idx.Modules {
+		if idx.Modules[i].Name == name {
+			return &idx.Modules[i]
+		}
+	}
+	return nil
+}
+
+func (m *RegistryModule) versionList() []string {
+	versions := make([]string, len(m.Versions))
+	for i, v := range m.Versions {
+		versions[i] = v.Version
+	}
+	return versions
+}
+
+func (m *RegistryModule) version(version string) (RegistryVersion, bool) {
+	for _, v := range m.Versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return RegistryVersion{}, false
+}
+
+// latest returns the highest release, or "" if the module has none.
+func (m *RegistryModule) latest() string {
+	version, _ := latestMatching(m.versionList(), "")
+	return version
+}
+
+// registrySources returns the registries to consult: the --registry flag if
+// given, otherwise $GOVITE_REGISTRY, otherwise the registries saved in the
+// CLI config.
+func registrySources(flag string) []string {
+	if flag != "" {
+		return []string{flag}
+	}
+	if env := os.Getenv(registryEnvVar); env != "" {
+		return strings.Split(env, ",")
+	}
+	data, err := loadData()
+	if err != nil {
+		return nil
+	}
+	return data.Registries
+}
+
+func isHTTPSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// registryDir returns the directory of a file-system registry.
+func registryDir(source string) string {
+	return strings.TrimPrefix(source, "file://")
+}
+
+var registryClient = &http.Client{Timeout: 60 * time.Second}
+
+// fetchRegistryFile reads a file relative to the root of a registry.
+func fetchRegistryFile(source, rel string) ([]byte, error) {
+	if isHTTPSource(source) {
+		url := strings.TrimSuffix(source, "/") + "/" + strings.TrimPrefix(rel, "/")
+		resp, err := registryClient.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	if !isRelativeSubpath(rel) {
+		return nil, fmt.Errorf("registry path %q escapes the registry", rel)
+	}
+	return os.ReadFile(filepath.Join(registryDir(source), filepath.FromSlash(rel)))
+}
+
+func loadRegistryIndex(source string) (*RegistryIndex, error) {
+	content, err := fetchRegistryFile(source, registryIndexFile)
+	if err != nil {
+		return nil, err
+	}
+	idx := &RegistryIndex{}
+	if err := json.Unmarshal(content, idx); err != nil {
+		return nil, fmt.Errorf("invalid registry index at %s: %w", source, err)
+	}
+	return idx, nil
+}
+
+// parseModuleRef splits "name@version". The leading @ of a scoped name is not
+// treated as a version separator.
+func parseModuleRef(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "@"); i > 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// registryMatch is a module found in a registry.
+type registryMatch struct {
+	Source string
+	Module RegistryModule
+}
+
+// searchRegistries returns modules whose name, description or tags contain
+// query, and which carry every tag in tags.
+func searchRegistries(sources []string, query string, tags []string) ([]registryMatch, error) {
+	query = strings.ToLower(query)
+	var matches []registryMatch
+	for _, source := range sources {
+		idx, err := loadRegistryIndex(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, module := range idx.Modules {
+			if moduleMatches(module, query, tags) {
+				matches = append(matches, registryMatch{Source: source, Module: module})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Module.Name < matches[j].Module.Name
+	})
+	return matches, nil
+}
+
+func moduleMatches(module RegistryModule, query string, tags []string) bool {
+	moduleTags := make(map[string]bool)
+	for _, tag := range module.Tags {
+		moduleTags[strings.ToLower(tag)] = true
+	}
+	for _, tag := range tags {
+		if !moduleTags[strings.ToLower(tag)] {
+			return false
+		}
+	}
+	if query == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(module.Name), query) || strings.Contains(strings.ToLower(module.Description), query) {
+		return true
+	}
+	for tag := range moduleTags {
+		if strings.Contains(tag, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRegistryModule finds the first registry that publishes name and
+// picks the highest version matching constraint. It returns a nil module if
+// no registry knows the name.
+func resolveRegistryModule(sources []string, name, constraint string) (string, *RegistryModule, RegistryVersion, error) {
+	for _, source := range sources {
+		idx, err := loadRegistryIndex(source)
+		if err != nil {
+			return "", nil, RegistryVersion{}, err
+		}
+		if module, v, err := idx.resolve(name, constraint); module != nil || err != nil {
+			return source, module, v, err
+		}
+	}
+	return "", nil, RegistryVersion{}, nil
+}
+
+// resolve picks the highest version of name matching constraint. It returns
+// a nil module if the index does not publish name.
+func (idx *RegistryIndex) resolve(name, constraint string) (*RegistryModule, RegistryVersion, error) {
+	module := idx.find(name)
+	if module == nil {
+		return nil, RegistryVersion{}, nil
+	}
+	version, ok := latestMatching(module.versionList(), constraint)
+	if !ok {
+		return nil, RegistryVersion{}, fmt.Errorf("no version of %s matches %q (available: %s)",
+			name, constraint, strings.Join(module.versionList(), ", "))
+	}
+	v, _ := module.version(version)
+	return module, v, nil
+}
+
+func registryCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "go-vite", "registry")
+}
+
+// fetchRegistryModule downloads, verifies and unpacks a module version into
+// the local cache and returns the unpacked directory. Versions already in the
+// cache are reused.
+func fetchRegistryModule(source, name string, v RegistryVersion) (string, error) {
+	if !validRegistryName(name) {
+		return "", fmt.Errorf("invalid module name %q", name)
+	}
+	if _, err := parseSemver(v.Version); err != nil {
+		return "", err
+	}
+
+	// The checksum is part of the cache key so a republished version is
+	// never served from a stale cache entry.
+	sum := strings.ToLower(v.SHA256)
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid checksum for %s@%s", name, v.Version)
+	}
+	dest := filepath.Join(registryCacheDir(), filepath.FromSlash(name), v.Version+"-"+sum[:12])
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	archive, err := fetchRegistryFile(source, v.Archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s@%s: %w", name, v.Version, err)
+	}
+	digest := sha256.Sum256(archive)
+	if got := hex.EncodeToString(digest[:]); got != sum {
+		return "", fmt.Errorf("checksum mismatch for %s@%s: expected %s, got %s", name, v.Version, v.SHA256, got)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractTarGz(bytes.NewReader(archive), tmp); err != nil {
+		return "", fmt.Errorf("failed to unpack %s@%s: %w", name, v.Version, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// extractTarGz unpacks regular files and directories into dest, rejecting
+// entries that would land outside it.
+func extractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if name == "." {
+			continue
+		}
+		if !isRelativeSubpath(name) {
+			return fmt.Errorf("archive entry %q escapes the module", hdr.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tr)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %q has unsupported type", hdr.Name)
+		}
+	}
+}
+
+// writeTarGz archives the module at src, leaving out the paths module copies
+// ignore. Only directories and regular files are archived.
+func writeTarGz(src string, w io.Writer) error {
+	src, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err = walkModuleTree(src, func(rel string, info os.FileInfo) error {
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		file, err := os.Open(filepath.Join(src, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// publishModule archives the module at modulePath into a file-system
+// registry and adds it to the registry index. The module is published under
+// the name it installs as, from go.mod or package.json, so installs, the lock
+// file, update and uninstall all use the same name.
+func publishModule(modulePath, registry, version string, force bool) (string, string, error) {
+	if isHTTPSource(registry) {
+		return "", "", fmt.Errorf("publishing is only supported to directory registries")
+	}
+	dir := registryDir(registry)
+
+	moduleType := detectLocalModuleType(modulePath)
+	if moduleType == Unknown {
+		return "", "", fmt.Errorf("cannot determine module type of %s", modulePath)
+	}
+	name := getModuleName(modulePath, moduleType)
+	if name == "" {
+		return "", "", fmt.Errorf("cannot determine module name of %s", modulePath)
+	}
+	manifest, err := loadModuleManifest(modulePath)
+	if err != nil {
+		return "", "", err
+	}
+	module := RegistryModule{Name: name}
+	if manifest != nil {
+		if manifest.Name != "" && manifest.Name != name {
+			return "", "", fmt.Errorf("%s names the module %q, but it installs as %q; use the same name", manifestFileName, manifest.Name, name)
+		}
+		if version == "" {
+			version = manifest.Version
+		}
+		module.Description = manifest.Description
+		module.Tags = manifest.Tags
+	}
+	if version == "" && moduleType == NodeProject {
+		if pkg, err := readNodePackage(modulePath); err == nil {
+			version = pkg.Version
+		}
+	}
+	if !validRegistryName(module.Name) {
+		return "", "", fmt.Errorf("invalid module name %q", module.Name)
+	}
+	v, err := parseSemver(version)
+	if err != nil {
+		return "", "", fmt.Errorf("a version is required to publish (use --version): %w", err)
+	}
+	version = v.String()
+
+	idx := &RegistryIndex{}
+	if content, err := os.ReadFile(filepath.Join(dir, registryIndexFile)); err == nil {
+		if err := json.Unmarshal(content, idx); err != nil {
+			return "", "", fmt.Errorf("invalid registry index: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", "", err
+	}
+
+	existing := idx.find(module.Name)
+	if existing != nil {
+		if _, ok := existing.version(version); ok && !force {
+			return "", "", fmt.Errorf("%s@%s is already published (use --force to overwrite)", module.Name, version)
+		}
+	}
+
+	archiveRel := module.Name + "/" + version + ".tar.gz"
+	archivePath := filepath.Join(dir, filepath.FromSlash(archiveRel))
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return "", "", err
+	}
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return "", "", err
+	}
+	hash := sha256.New()
+	if err := writeTarGz(modulePath, io.MultiWriter(file, hash)); err != nil {
+		file.Close()
+		return "", "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", "", err
+	}
+
+	entry := RegistryVersion{Version: version, Archive: archiveRel, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	if existing == nil {
+		idx.Modules = append(idx.Modules, module)
+		existing = &idx.Modules[len(idx.Modules)-1]
+	} else {
+		existing.Description = module.Description
+		existing.Tags = module.Tags
+	}
+	replaced := false
+	for i := range existing.Versions {
+		if existing.Versions[i].Version == version {
+			existing.Versions[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		existing.Versions = append(existing.Versions, entry)
+	}
+	sort.Slice(idx.Modules, func(i, j int) bool { return idx.Modules[i].Name < idx.Modules[j].Name })
+
+	content, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, registryIndexFile), append(content, '\n'), 0644); err != nil {
+		return "", "", err
+	}
+	return module.Name, version, nil
+}
+
+// installFromRegistry installs ref ("name" or "name@version") from the first
+// registry that publishes it. It returns false if no registry knows the name,
+// so the caller can fall back to go get / npm install. Unreachable registries
+// only warn, unless a registry after them publishes the name, since the
+// unreachable one might have published it first.
+func installFromRegistry(ref string, sources []string, opts installOptions) (bool, error) {
+	name, constraint := parseModuleRef(ref)
+	var unreachable error
+	for _, source := range sources {
+		idx, err := loadRegistryIndex(source)
+		if err != nil {
+			if unreachable == nil {
+				unreachable = err
+			}
+			continue
+		}
+		module, v, err := idx.resolve(name, constraint)
+		if err != nil {
+			return false, err
+		}
+		if module == nil {
+			continue
+		}
+		if unreachable != nil {
+			return false, fmt.Errorf("cannot tell which registry publishes %s: %w", name, unreachable)
+		}
+
+		fmt.Printf("Resolved %s@%s from %s\n", module.Name, v.Version, source)
+		dir, err := fetchRegistryModule(source, module.Name, v)
+		if err != nil {
+			return false, err
+		}
+		// Older registries may list a module under another name than the one
+		// it installs as, which the lock file and uninstall would not find
+		if installName := getModuleName(dir, detectLocalModuleType(dir)); installName != module.Name {
+			return false, fmt.Errorf("%s@%s from %s installs as %q; republish it under that name",
+				module.Name, v.Version, source, installName)
+		}
+
+		opts.Source = "registry"
+		opts.Origin = source
+		opts.Version = v.Version
+		if opts.Registries == nil {
+			opts.Registries = sources
+		}
+		return true, installLocal(dir, opts)
+	}
+	if unreachable != nil {
+		fmt.Printf("Warning: skipping unreachable registry: %v\n", unreachable)
+	}
+	return false, nil
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	registry, _ := cmd.Flags().GetString("registry")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	sources := registrySources(registry)
+	if len(sources) == 0 {
+		return fmt.Errorf("no registry configured (use --registry, $%s or 'go-vite registry add')", registryEnvVar)
+	}
+
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	matches, err := searchRegistries(sources, query, tags)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Println("No modules found")
+		return nil
+	}
+	for _, match := range matches {
+		fmt.Printf("%-32s %-10s %s\n", match.Module.Name, match.Module.latest(), match.Module.Description)
+		if len(match.Module.Tags) > 0 {
+			fmt.Printf("%-32s %-10s tags: %s\n", "", "", strings.Join(match.Module.Tags, ", "))
+		}
+	}
+	return nil
+}
+
+func runPublish(cmd *cobra.Command, args []string) error {
+	registry, _ := cmd.Flags().GetString("registry")
+	version, _ := cmd.Flags().GetString("version")
+	force, _ := cmd.Flags().GetBool("force")
+	if registry == "" {
+		return fmt.Errorf("--registry is required")
+	}
+
+	modulePath := "."
+	if len(args) > 0 {
+		modulePath = args[0]
+	}
+	name, version, err := publishModule(modulePath, registry, version, force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Published %s@%s to %s\n", name, version, registry)
+	return nil
+}
+
+func runRegistryAdd(cmd *cobra.Command, args []string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	for _, r := range data.Registries {
+		if r == args[0] {
+			return nil
+		}
+	}
+	data.Registries = append(data.Registries, args[0])
+	return saveData(data)
+}
+
+func runRegistryRemove(cmd *cobra.Command, args []string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	for i, r := range data.Registries {
+		if r == args[0] {
+			data.Registries = append(data.Registries[:i], data.Registries[i+1:]...)
+			return saveData(data)
+		}
+	}
+	return fmt.Errorf("registry %s is not configured", args[0])
+}
+
+func runRegistryList(cmd *cobra.Command, args []string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	for _, r := range data.Registries {
+		fmt.Println(r)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestNodeModule creates a Node module with a manifest under dir.
+func writeTestNodeModule(t *testing.T, dir, name, version string) {
+	os.MkdirAll(filepath.Join(dir, "node_modules", "left-pad"), 0755)
+	files := map[string]string{
+		"package.json":                       `{"name": "` + name + `", "version": "` + version + `"}`,
+		"index.js":                           "module.exports = {}\n",
+		"node_modules/left-pad/package.json": `{"name": "left-pad"}`,
+		manifestFileName:                     `{"name": "` + name + `", "version": "` + version + `", "description": "Export tables as CSV", "tags": ["csv", "export"]}`,
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+}
+
+// newTestRegistry publishes two versions of a module into a new directory
+// registry and points the module cache at a temporary directory.
+func newTestRegistry(t *testing.T) string {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	registry := t.TempDir()
+
+	for _, version := range []string{"1.1.0", "1.2.0"} {
+		moduleDir := t.TempDir()
+		writeTestNodeModule(t, moduleDir, "@ourorg/csv-export", version)
+		if _, _, err := publishModule(moduleDir, registry, "", false); err != nil {
+			t.Fatalf("publishModule failed: %v", err)
+		}
+	}
+	return registry
+}
+
+func TestParseModuleRef(t *testing.T) {
+	cases := map[string][2]string{
+		"@ourorg/csv-export@1.2": {"@ourorg/csv-export", "1.2"},
+		"@ourorg/csv-export":     {"@ourorg/csv-export", ""},
+		"reports@2.0.0":          {"reports", "2.0.0"},
+		"reports":                {"reports", ""},
+	}
+	for ref, expected := range cases {
+		name, version := parseModuleRef(ref)
+		if name != expected[0] || version != expected[1] {
+			t.Fatalf("parseModuleRef(%q) = %q, %q", ref, name, version)
+		}
+	}
+}
+
+func TestPublishModule(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	idx, err := loadRegistryIndex(registry)
+	if err != nil {
+		t.Fatalf("loadRegistryIndex failed: %v", err)
+	}
+	module := idx.find("@ourorg/csv-export")
+	if module == nil || len(module.Versions) != 2 {
+		t.Fatalf("Expected two published versions, got %+v", idx)
+	}
+	if module.latest() != "1.2.0" {
+		t.Fatalf("Expected latest 1.2.0, got %s", module.latest())
+	}
+
+	moduleDir := t.TempDir()
+	writeTestNodeModule(t, moduleDir, "@ourorg/csv-export", "1.2.0")
+	if _, _, err := publishModule(moduleDir, registry, "", false); err == nil {
+		t.Fatal("Expected republishing a version to fail without force")
+	}
+	if _, _, err := publishModule(moduleDir, registry, "", true); err != nil {
+		t.Fatalf("Expected forced republish to succeed, got %v", err)
+	}
+}
+
+func TestPublishGoModuleName(t *testing.T) {
+	registry := newTestRegistry(t)
+	moduleDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/widgets\n\ngo 1.22\n",
+		"widgets.go":     "package widgets\n",
+		manifestFileName: `{"name": "widgets", "version": "1.0.0"}`,
+	}
+	for file, content := range files {
+		os.WriteFile(filepath.Join(moduleDir, file), []byte(content), 0644)
+	}
+
+	if _, _, err := publishModule(moduleDir, registry, "", false); err == nil || !strings.Contains(err.Error(), `installs as "example.com/widgets"`) {
+		t.Fatalf("Expected a manifest name that differs from go.mod to be rejected, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(moduleDir, manifestFileName), []byte(`{"version": "1.0.0"}`), 0644)
+	name, _, err := publishModule(moduleDir, registry, "", false)
+	if err != nil || name != "example.com/widgets" {
+		t.Fatalf("Expected the module to be published as its Go module path, got %q %v", name, err)
+	}
+}
+
+func TestSearchRegistries(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	for _, query := range []string{"csv-exp", "TABLES", "expor"} {
+		matches, err := searchRegistries([]string{registry}, query, nil)
+		if err != nil {
+			t.Fatalf("searchRegistries failed: %v", err)
+		}
+		if len(matches) != 1 {
+			t.Fatalf("Expected one match for %q, got %d", query, len(matches))
+		}
+	}
+
+	matches, _ := searchRegistries([]string{registry}, "", []string{"csv", "export"})
+	if len(matches) != 1 {
+		t.Fatal("Expected tag filter to match")
+	}
+
+	matches, _ = searchRegistries([]string{registry}, "", []string{"pdf"})
+	if len(matches) != 0 {
+		t.Fatal("Expected tag filter to exclude the module")
+	}
+}
+
+func TestFetchRegistryModule(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	source, module, v, err := resolveRegistryModule([]string{registry}, "@ourorg/csv-export", "1.1")
+	if err != nil || module == nil {
+		t.Fatalf("resolveRegistryModule failed: %v", err)
+	}
+	if v.Version != "1.1.0" {
+		t.Fatalf("Expected 1.1.0, got %s", v.Version)
+	}
+
+	dir, err := fetchRegistryModule(source, module.Name, v)
+	if err != nil {
+		t.Fatalf("fetchRegistryModule failed: %v", err)
+	}
+	if getNodeModuleName(dir) != "@ourorg/csv-export" {
+		t.Fatal("Expected unpacked module in cache")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node_modules")); !os.IsNotExist(err) {
+		t.Fatal("Expected node_modules to be left out of the archive")
+	}
+
+	_, module, _, err = resolveRegistryModule([]string{registry}, "unknown-module", "")
+	if err != nil || module != nil {
+		t.Fatal("Expected unknown module to resolve to nothing")
+	}
+
+	if _, _, _, err := resolveRegistryModule([]string{registry}, "@ourorg/csv-export", "3"); err == nil {
+		t.Fatal("Expected unsatisfiable version to fail")
+	}
+}
+
+func TestFetchRegistryModuleOverHTTP(t *testing.T) {
+	registry := newTestRegistry(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(registry)))
+	defer server.Close()
+
+	source, module, v, err := resolveRegistryModule([]string{server.URL}, "@ourorg/csv-export", "")
+	if err != nil || module == nil {
+		t.Fatalf("resolveRegistryModule failed: %v", err)
+	}
+	if _, err := fetchRegistryModule(source, module.Name, v); err != nil {
+		t.Fatalf("fetchRegistryModule failed: %v", err)
+	}
+}
+
+func TestFetchRegistryModuleChecksumMismatch(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	_, module, v, _ := resolveRegistryModule([]string{registry}, "@ourorg/csv-export", "1.2.0")
+	archive := filepath.Join(registry, filepath.FromSlash(v.Archive))
+	os.WriteFile(archive, []byte("tampered"), 0644)
+
+	_, err := fetchRegistryModule(registry, module.Name, v)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestExtractTarGzRejectsEscapes(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+	tw.Write([]byte("evil"))
+	tw.Close()
+	gz.Close()
+
+	if err := extractTarGz(&buf, t.TempDir()); err == nil {
+		t.Fatal("Expected archive entry outside the destination to be rejected")
+	}
+}
+
+func TestInstallFromRegistry(t *testing.T) {
+	registry := newTestRegistry(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	if installed, err := installFromRegistry("not-published", []string{registry}, installOptions{}); installed || err != nil {
+		t.Fatalf("Expected unknown module to fall through to the package manager, got %v", err)
+	}
+
+	// An unreachable registry must not block go get / npm install
+	offline := filepath.Join(t.TempDir(), "missing")
+	if installed, err := installFromRegistry("github.com/gin-gonic/gin", []string{offline, registry}, installOptions{}); installed || err != nil {
+		t.Fatalf("Expected an unreachable registry to be skipped, got %v", err)
+	}
+	if _, err := installFromRegistry("@ourorg/csv-export", []string{offline, registry}, installOptions{}); err == nil {
+		t.Fatal("Expected an error when an earlier registry might publish the module")
+	}
+
+	if installed, err := installFromRegistry("@ourorg/csv-export@1.1", []string{registry}, installOptions{}); !installed || err != nil {
+		t.Fatalf("Expected module to be installed from the registry, got %v", err)
+	}
+
+	content, _ := os.ReadFile(packageJSONFile)
+	if !strings.Contains(string(content), `"@ourorg/csv-export": "file:./modules/@ourorg/csv-export"`) {
+		t.Fatalf("Expected module in package.json, got: %s", content)
+	}
+
+	if source, ok := findLocalModule("@ourorg/csv-export"); !ok || source != "registry" {
+		t.Fatalf("Expected module to be recorded as a registry install, got %q", source)
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const requirementsFileName = "modules.yaml"
+
+// Requirement is one entry of modules.yaml. Exactly one of Go, Npm, Path and
+// Git names what to install:
+//
+//	modules:
+//	  - go: github.com/google/uuid
+//	    version: v1.6.0
+//	  - npm: zod@3.22.4
+//	    target: frontend
+//	  - path: ../shared/auth
+//	  - git: git+https://github.com/acme/modules//csv-export
+//	    version: v1.2.0
+//
+// Version pins a Go or npm package, or picks the ref of a git source. Target
+// is the directory go get or npm install runs in. Alias is the Vite import
+// alias of a Node.js go-vite module. Relative paths and targets are resolved
+// against the directory of the file, not the working directory.
+type Requirement struct {
+	Go      string
+	Npm     string
+	Path    string
+	Git     string
+	Version string
+	Target  string
+	Alias   string
+	Line    int
+}
+
+func (r Requirement) kind() string {
+	switch {
+	case r.Go != "":
+		return "go"
+	case r.Npm != "":
+		return "npm"
+	case r.Path != "":
+		return "path"
+	default:
+		return "git"
+	}
+}
+
+// spec is the argument handed to go get, npm install or install-local.
+func (r Requirement) spec() string {
+	switch r.kind() {
+	case "go", "npm":
+		name := r.Go + r.Npm
+		if r.Version != "" {
+			return name + "@" + r.Version
+		}
+		return name
+	case "path":
+		return r.Path
+	default:
+		return r.Git
+	}
+}
+
+func (r Requirement) String() string {
+	if r.Target != "" {
+		return fmt.Sprintf("%s -> %s", r.spec(), r.Target)
+	}
+	return r.spec()
+}
+
+var requirementFields = map[string]bool{
+	"go": true, "npm": true, "path": true, "git": true,
+	"version": true, "target": true, "alias": true,
+}
+
+// loadRequirements reads a modules.yaml. Relative paths are resolved against
+// the file's directory.
+func loadRequirements(file string) ([]Requirement, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a map with a modules list", file)
+	}
+	var list *yaml.Node
+	for i := 0; i < len(root.Content); i += 2 {
+		key := root.Content[i]
+		if key.Value != "modules" {
+			return nil, fmt.Errorf("%s: unknown key %q (expected modules)", file, key.Value)
+		}
+		if list != nil {
+			return nil, fmt.Errorf("%s:%d: duplicate key modules", file, key.Line)
+		}
+		list = root.Content[i+1]
+	}
+	if list == nil || (list.Kind == yaml.ScalarNode && list.Tag == "!!null") {
+		return nil, nil
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s:%d: modules must be a list", file, list.Line)
+	}
+
+	var reqs []Requirement
+	for _, item := range list.Content {
+		req, err := parseRequirement(item, filepath.Dir(file))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, item.Line, err)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func parseRequirement(item *yaml.Node, baseDir string) (Requirement, error) {
+	req := Requirement{Line: item.Line}
+	if item.Kind != yaml.MappingNode {
+		return req, fmt.Errorf("each module must be a map with go, npm, path or git")
+	}
+	fields := make(map[string]string)
+	for i := 0; i < len(item.Content); i += 2 {
+		key, value := item.Content[i].Value, item.Content[i+1]
+		if !requirementFields[key] {
+			return req, fmt.Errorf("unknown field %q", key)
+		}
+		if _, ok := fields[key]; ok {
+			return req, fmt.Errorf("duplicate field %q", key)
+		}
+		if value.Kind != yaml.ScalarNode {
+			return req, fmt.Errorf("%s must be a string", key)
+		}
+		fields[key] = value.Value
+	}
+	req.Go, req.Npm, req.Path, req.Git = fields["go"], fields["npm"], fields["path"], fields["git"]
+	req.Version, req.Target, req.Alias = fields["version"], fields["target"], fields["alias"]
+
+	set := 0
+	for _, name := range []string{req.Go, req.Npm, req.Path, req.Git} {
+		if name != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return req, fmt.Errorf("each module needs exactly one of go, npm, path or git")
+	}
+
+	switch req.kind() {
+	case "go", "npm":
+		name, version := parseModuleRef(req.Go + req.Npm)
+		if version != "" {
+			if req.Version != "" {
+				return req, fmt.Errorf("%s pins a version both in its name and in version", name)
+			}
+			req.Version = version
+		}
+		// Both end up on the go get or npm install command line
+		if strings.HasPrefix(name, "-") {
+			return req, fmt.Errorf("%s name %q must not start with '-'", req.kind(), name)
+		}
+		if strings.HasPrefix(req.Version, "-") {
+			return req, fmt.Errorf("version %q must not start with '-'", req.Version)
+		}
+		if req.kind() == "go" {
+			req.Go = name
+		} else {
+			req.Npm = name
+		}
+		if req.Alias != "" {
+			return req, fmt.Errorf("alias only applies to path and git modules")
+		}
+		if req.Target != "" && req.Target != "." && !isRelativeSubpath(req.Target) {
+			return req, fmt.Errorf("target %q must be a directory inside the project", req.Target)
+		}
+		if req.Target == "" {
+			req.Target = defaultTarget(req.kind(), baseDir)
+		} else {
+			req.Target = filepath.Join(baseDir, req.Target)
+		}
+	case "path":
+		if req.Version != "" || req.Target != "" {
+			return req, fmt.Errorf("path modules take no version or target")
+		}
+		if !filepath.IsAbs(req.Path) {
+			req.Path = filepath.Join(baseDir, req.Path)
+		}
+	case "git":
+		if req.Target != "" {
+			return req, fmt.Errorf("git modules take no target")
+		}
+		src, err := parseGitSource(req.Git)
+		if err != nil {
+			return req, err
+		}
+		if req.Version != "" {
+			if src.Ref != "" {
+				return req, fmt.Errorf("%s pins a ref both in its URL and in version", req.Git)
+			}
+			src.Ref = req.Version
+		}
+		req.Git, req.Version = src.String(), ""
+	}
+	return req, nil
+}
+
+// defaultTarget is where go get or npm install runs when an entry has no
+// target: the backend or frontend of the go-vite project in baseDir, else
+// baseDir itself.
+func defaultTarget(kind, baseDir string) string {
+	switch kind {
+	case "go":
+		if _, err := os.Stat(filepath.Join(baseDir, backendGoModFile)); err == nil {
+			return filepath.Join(baseDir, "backend")
+		}
+	case "npm":
+		if _, err := os.Stat(filepath.Join(baseDir, packageJSONFile)); err == nil {
+			return filepath.Join(baseDir, frontendDir)
+		}
+	}
+	return baseDir
+}
+
+// runPackageManager runs go or npm in dir and returns its combined output.
+var runPackageManager = func(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// installResult is one line of the consolidated install report.
+type installResult struct {
+	Req Requirement
+	Err error
+}
+
+// installPackages installs the Go or npm entries of one ecosystem with one
+// go get or npm install per target directory.
+func installPackages(kind string, reqs []Requirement) []installResult {
+	var targets []string
+	byTarget := make(map[string][]Requirement)
+	for _, req := range reqs {
+		if _, ok := byTarget[req.Target]; !ok {
+			targets = append(targets, req.Target)
+		}
+		byTarget[req.Target] = append(byTarget[req.Target], req)
+	}
+
+	var results []installResult
+	for _, target := range targets {
+		batch := byTarget[target]
+		manifest := "go.mod"
+		args := []string{"get"}
+		if kind == "npm" {
+			manifest = "package.json"
+			args = []string{"install"}
+		}
+		var err error
+		if _, statErr := os.Stat(filepath.Join(target, manifest)); statErr != nil {
+			err = fmt.Errorf("target %s has no %s", target, manifest)
+		} else {
+			for _, req := range batch {
+				args = append(args, req.spec())
+			}
+			if output, runErr := runPackageManager(target, kind, args...); runErr != nil {
+				err = fmt.Errorf("%s %s failed: %v\n%s", kind, args[0], runErr, strings.TrimSpace(string(output)))
+			}
+		}
+		for _, req := range batch {
+			results = append(results, installResult{Req: req, Err: err})
+		}
+	}
+	return results
+}
+
+// installRequirements installs every entry and prints one report. Go and npm
+// packages install concurrently. go-vite modules install after them, one at
+// a time, because they edit the same go.mod and package.json.
+func installRequirements(reqs []Requirement, opts installOptions) error {
+	lanes := map[string][]Requirement{}
+	var modules []Requirement
+	for _, req := range reqs {
+		switch req.kind() {
+		case "go", "npm":
+			lanes[req.kind()] = append(lanes[req.kind()], req)
+		default:
+			modules = append(modules, req)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	laneResults := map[string][]installResult{}
+	for kind, laneReqs := range lanes {
+		fmt.Printf("Installing %d %s packages...\n", len(laneReqs), kind)
+		wg.Add(1)
+		go func(kind string, laneReqs []Requirement) {
+			defer wg.Done()
+			results := installPackages(kind, laneReqs)
+			mu.Lock()
+			laneResults[kind] = results
+			mu.Unlock()
+		}(kind, laneReqs)
+	}
+	wg.Wait()
+
+	var results []installResult
+	for _, kind := range []string{"go", "npm"} {
+		for _, result := range laneResults[kind] {
+			if result.Err == nil {
+				saveInstalledModule(result.Req.spec())
+			}
+			results = append(results, result)
+		}
+	}
+
+	for _, req := range modules {
+		moduleOpts := opts
+		moduleOpts.Alias = req.Alias
+		var err error
+		if req.kind() == "git" {
+			err = installGitModule(req.Git, moduleOpts)
+		} else {
+			err = installLocal(req.Path, moduleOpts)
+		}
+		results = append(results, installResult{Req: req, Err: err})
+	}
+
+	return printInstallReport(results)
+}
+
+func printInstallReport(results []installResult) error {
+	failed := 0
+	fmt.Println("\n📋 Install report:")
+	for _, result := range results {
+		if result.Err == nil {
+			fmt.Printf("  ✅ %-4s %s\n", result.Req.kind(), result.Req)
+			continue
+		}
+		failed++
+		fmt.Printf("  ❌ %-4s %s\n", result.Req.kind(), result.Req)
+		for _, line := range strings.Split(result.Err.Error(), "\n") {
+			fmt.Printf("       %s\n", line)
+		}
+	}
+	fmt.Printf("%d of %d installed\n", len(results)-failed, len(results))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d installs failed", failed, len(results))
+	}
+	return nil
+}
+
+// installRequirementsFile installs everything listed in a modules.yaml. It
+// runs in the file's directory, so path and git modules install into the
+// same project as the go and npm entries wherever the command was started.
+func installRequirementsFile(file string, opts installOptions) error {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	reqs, err := loadRequirements(path)
+	if err != nil {
+		return err
+	}
+	if len(reqs) == 0 {
+		fmt.Printf("No modules listed in %s\n", file)
+		return nil
+	}
+	return installRequirements(reqs, opts)
+}
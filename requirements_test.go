@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubPackageManager records go and npm invocations instead of running them.
+func stubPackageManager(t *testing.T, fn func(dir, name string, args ...string) ([]byte, error)) {
+	old := runPackageManager
+	runPackageManager = fn
+	t.Cleanup(func() { runPackageManager = old })
+}
+
+func TestLoadRequirements(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, requirementsFileName)
+	os.WriteFile(file, []byte(`modules:
+  - go: github.com/google/uuid@v1.6.0
+  - npm: "@tanstack/react-query"
+    version: ^5.0.0
+    target: web
+  - path: ./shared/auth
+    alias: "@auth"
+  - git: git+https://example.com/acme/modules.git//csv-export
+    version: v1.2.0
+`), 0644)
+
+	reqs, err := loadRequirements(file)
+	if err != nil {
+		t.Fatalf("loadRequirements failed: %v", err)
+	}
+	if len(reqs) != 4 {
+		t.Fatalf("Expected 4 requirements, got %d", len(reqs))
+	}
+	if reqs[0].Go != "github.com/google/uuid" || reqs[0].Version != "v1.6.0" || reqs[0].Line != 2 {
+		t.Fatalf("Unexpected go requirement %+v", reqs[0])
+	}
+	if reqs[1].spec() != "@tanstack/react-query@^5.0.0" || reqs[1].Target != filepath.Join(dir, "web") {
+		t.Fatalf("Unexpected npm requirement %+v", reqs[1])
+	}
+	if reqs[2].Path != filepath.Join(dir, "shared", "auth") || reqs[2].Alias != "@auth" {
+		t.Fatalf("Expected the path relative to the file, got %+v", reqs[2])
+	}
+	if reqs[3].Git != "git+https://example.com/acme/modules.git//csv-export@v1.2.0" {
+		t.Fatalf("Expected version to become the git ref, got %q", reqs[3].Git)
+	}
+}
+
+func TestLoadRequirementsErrors(t *testing.T) {
+	tests := map[string]string{
+		"modules:\n  - go: a\n    npm: b\n":                      ":2: each module needs exactly one of",
+		"modules:\n  - go: a@v1\n    version: v2\n":              ":2: a pins a version both",
+		"modules:\n  - path: ./x\n    target: backend\n":         ":2: path modules take no version or target",
+		"modules:\n  - npm: a\n    targte: web\n":                ":2: unknown field \"targte\"",
+		"modules:\n  - go: a\n    target: ../elsewhere\n":        "must be a directory inside the project",
+		"modules:\n  - git: file:///r.git@v1\n    version: v2\n": "pins a ref both",
+		"module:\n  - go: a\n":                                   "unknown key \"module\" (expected modules)",
+		"modules:\n  - go: a\n    go: b\n":                       ":2: duplicate field \"go\"",
+		"modules:\n  - go: [a, b]\n":                             ":2: go must be a string",
+		"modules:\n  - go: \"open\n":                             "line 2",
+		"modules:\n  - npm: --registry=http://evil\n":            "npm name \"--registry=http://evil\" must not start with '-'",
+		"modules:\n  - go: a\n    version: -x\n":                 "version \"-x\" must not start with '-'",
+	}
+	for content, want := range tests {
+		file := filepath.Join(t.TempDir(), requirementsFileName)
+		os.WriteFile(file, []byte(content), 0644)
+		_, err := loadRequirements(file)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loadRequirements(%q) = %v, want error containing %q", content, err, want)
+		}
+	}
+}
+
+func TestInstallRequirements(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	var mu sync.Mutex
+	var calls []string
+	stubPackageManager(t, func(dir, name string, args ...string) ([]byte, error) {
+		mu.Lock()
+		calls = append(calls, fmt.Sprintf("%s: %s %s", dir, name, strings.Join(args, " ")))
+		mu.Unlock()
+		if name == "npm" {
+			return []byte("npm ERR! 404 Not Found"), fmt.Errorf("exit status 1")
+		}
+		return nil, nil
+	})
+
+	source := filepath.Join(t.TempDir(), "widgets")
+	os.MkdirAll(source, 0755)
+	writeTestNodeModule(t, source, "widgets", "1.0.0")
+	os.WriteFile(requirementsFileName, []byte(`modules:
+  - go: github.com/google/uuid
+    version: v1.6.0
+  - go: github.com/pkg/errors@v0.9.1
+  - npm: left-pad
+  - path: `+source+`
+    alias: "@widgets"
+`), 0644)
+
+	err := installRequirementsFile(requirementsFileName, installOptions{})
+	if err == nil || err.Error() != "1 of 4 installs failed" {
+		t.Fatalf("Expected one failed install, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	got := strings.Join(calls, "\n")
+	if !strings.Contains(got, "backend: go get github.com/google/uuid@v1.6.0 github.com/pkg/errors@v0.9.1") {
+		t.Fatalf("Expected one go get for the backend, got:\n%s", got)
+	}
+	if !strings.Contains(got, "frontend: npm install left-pad") {
+		t.Fatalf("Expected npm install in the frontend, got:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(frontendModulesDir, "widgets", "index.js")); err != nil {
+		t.Fatalf("Expected the local module to be installed: %v", err)
+	}
+
+	wd, _ := os.Getwd()
+	data, _ := loadData()
+	installed := strings.Join(data.InstalledModules[wd], ",")
+	if !strings.Contains(installed, "github.com/google/uuid@v1.6.0") || strings.Contains(installed, "left-pad") {
+		t.Fatalf("Expected only successful installs to be recorded, got %s", installed)
+	}
+}
+
+func TestInstallRequirementsFromAnotherDirectory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	var mu sync.Mutex
+	var dirs []string
+	stubPackageManager(t, func(dir, name string, args ...string) ([]byte, error) {
+		mu.Lock()
+		dirs = append(dirs, dir)
+		mu.Unlock()
+		return nil, nil
+	})
+
+	// modules.yaml belongs to another project than the working directory
+	other := t.TempDir()
+	for _, file := range []string{backendGoModFile, "web/package.json"} {
+		os.MkdirAll(filepath.Dir(filepath.Join(other, file)), 0755)
+		os.WriteFile(filepath.Join(other, file), []byte("{}"), 0644)
+	}
+	file := filepath.Join(other, requirementsFileName)
+	os.WriteFile(file, []byte(`modules:
+  - go: github.com/google/uuid@v1.6.0
+  - npm: left-pad
+    target: web
+`), 0644)
+
+	if err := installRequirementsFile(file, installOptions{}); err != nil {
+		t.Fatalf("installRequirementsFile failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	got := strings.Join(dirs, "\n")
+	for _, want := range []string{filepath.Join(other, "backend"), filepath.Join(other, "web")} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Expected go and npm to run in %s, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestInstallRequirementsModulesIntoFileProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project, cleanup := newTestProject(t)
+	defer cleanup()
+
+	writeTestNodeModule(t, filepath.Join(project, "widgets-src"), "widgets", "1.0.0")
+	os.WriteFile(requirementsFileName, []byte("modules:\n  - path: ./widgets-src\n"), 0644)
+
+	// The command starts outside the project the file belongs to
+	elsewhere := t.TempDir()
+	os.Chdir(elsewhere)
+	if err := installRequirementsFile(filepath.Join(project, requirementsFileName), installOptions{}); err != nil {
+		t.Fatalf("installRequirementsFile failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(project, frontendModulesDir, "widgets", "index.js")); err != nil {
+		t.Fatalf("Expected the module to be installed into the file's project: %v", err)
+	}
+	if entries, _ := os.ReadDir(elsewhere); len(entries) != 0 {
+		t.Fatalf("Expected nothing to be written to the working directory, got %d entries", len(entries))
+	}
+	if wd, _ := os.Getwd(); wd != elsewhere {
+		t.Fatalf("Expected the working directory to be restored, got %s", wd)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var moduleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ModuleScaffold describes a standalone module project created by
+// `go-vite module new`.
+type ModuleScaffold struct {
+	// Name is the module name used in the manifest and for registration.
+	Name string
+	// Module is the Go module path, Name by default.
+	Module      string
+	Description string
+}
+
+func (s ModuleScaffold) packageName() string {
+	return goAlias(s.Module)
+}
+
+// componentName is the exported name of the example React component,
+// e.g. "csv-export" -> "CsvExport".
+func (s ModuleScaffold) componentName() string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s.Name {
+		switch {
+		case r >= 'a' && r <= 'z':
+			if upper {
+				r -= 'a' - 'A'
+			}
+			b.WriteRune(r)
+			upper = false
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "Module" + name
+	}
+	return name
+}
+
+func runModuleNew(cmd *cobra.Command, args []string) error {
+	goModule, _ := cmd.Flags().GetString("module")
+	description, _ := cmd.Flags().GetString("description")
+
+	scaffold := ModuleScaffold{Name: args[0], Module: goModule, Description: description}
+	if err := createModuleScaffold(args[0], scaffold); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Module '%s' created successfully!\n", scaffold.Name)
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  cd %s\n", args[0])
+	fmt.Printf("  go test ./...\n")
+	fmt.Printf("  cd ../your-project && go-vite install-local ../%s\n", args[0])
+	return nil
+}
+
+// createModuleScaffold writes a new module project to dir, which must not
+// exist yet.
+func createModuleScaffold(dir string, scaffold ModuleScaffold) error {
+	if !moduleNamePattern.MatchString(scaffold.Name) {
+		return fmt.Errorf("invalid module name %q: use letters, digits, '.', '_' and '-'", scaffold.Name)
+	}
+	if scaffold.Module == "" {
+		scaffold.Module = scaffold.Name
+	}
+	if scaffold.Description == "" {
+		scaffold.Description = "A go-vite module"
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("directory %s already exists", dir)
+	}
+
+	pkg := scaffold.packageName()
+	component := scaffold.componentName()
+	files := map[string]string{
+		"go.mod":                    generateModuleScaffoldGoMod(scaffold),
+		pkg + ".go":                 generateModuleScaffoldGo(scaffold),
+		pkg + "_test.go":            generateModuleScaffoldTest(scaffold),
+		manifestFileName:            generateModuleScaffoldManifest(scaffold),
+		"web/" + component + ".tsx": generateModuleScaffoldComponent(scaffold),
+		"README.md":                 generateModuleScaffoldReadme(scaffold),
+		gitIgnoreFile:               generateModuleScaffoldGitignore(),
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateModuleScaffoldGoMod(s ModuleScaffold) string {
+	return fmt.Sprintf(`module %s
+
+go 1.23
+`, s.Module)
+}
+
+func generateModuleScaffoldGo(s ModuleScaffold) string {
+	return fmt.Sprintf(`// Package %[1]s is a go-vite backend module.
+package %[1]s
+
+import "fmt"
+
+// Module implements the Module interface of a go-vite backend
+// (backend/internal/modules/modules.go). The interface is satisfied
+// structurally, so this package doesn't import the backend.
+type Module struct {
+	config map[string]interface{}
+}
+
+// New returns the module. go-vite registers it in LoadBuiltinModules as
+// manager.Register(%[2]q, %[1]s.New()).
+func New() *Module {
+	return &Module{config: map[string]interface{}{}}
+}
+
+// Name returns the name the module is registered under.
+func (m *Module) Name() string {
+	return %[2]q
+}
+
+// Execute runs the module with the given input.
+func (m *Module) Execute(input map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"module": m.Name(),
+		"config": m.config,
+		"input":  input,
+	}, nil
+}
+
+// Validate checks and stores the module configuration.
+func (m *Module) Validate(config map[string]interface{}) error {
+	for key := range config {
+		if key == "" {
+			return fmt.Errorf("%%s: config keys must not be empty", m.Name())
+		}
+	}
+	m.config = config
+	return nil
+}
+`, s.packageName(), s.Name)
+}
+
+func generateModuleScaffoldTest(s ModuleScaffold) string {
+	return fmt.Sprintf(`package %[1]s
+
+import "testing"
+
+// backendModule mirrors the Module interface of a go-vite backend.
+type backendModule interface {
+	Name() string
+	Execute(input map[string]interface{}) (map[string]interface{}, error)
+	Validate(config map[string]interface{}) error
+}
+
+var _ backendModule = New()
+
+func TestName(t *testing.T) {
+	if New().Name() != %[2]q {
+		t.Fatalf("Expected name %%q, got %%q", %[2]q, New().Name())
+	}
+}
+
+func TestExecute(t *testing.T) {
+	m := New()
+	if err := m.Validate(map[string]interface{}{"enabled": true}); err != nil {
+		t.Fatalf("Validate failed: %%v", err)
+	}
+
+	result, err := m.Execute(map[string]interface{}{"value": 1})
+	if err != nil {
+		t.Fatalf("Execute failed: %%v", err)
+	}
+	if result["module"] != %[2]q {
+		t.Fatalf("Unexpected result: %%v", result)
+	}
+}
+
+func TestValidateRejectsEmptyKeys(t *testing.T) {
+	if err := New().Validate(map[string]interface{}{"": 1}); err == nil {
+		t.Fatal("Expected an empty config key to be rejected")
+	}
+}
+`, s.packageName(), s.Name)
+}
+
+func generateModuleScaffoldManifest(s ModuleScaffold) string {
+	manifest := ModuleManifest{
+		Name:        s.Name,
+		Version:     "0.1.0",
+		Description: s.Description,
+		Go:          &ManifestGo{Register: s.Name, Constructor: defaultConstructor},
+		Frontend:    &ManifestFrontend{Components: []string{"web/" + s.componentName() + ".tsx"}},
+	}
+	content, _ := json.MarshalIndent(manifest, "", "  ")
+	return string(content) + "\n"
+}
+
+func generateModuleScaffoldComponent(s ModuleScaffold) string {
+	return fmt.Sprintf(`interface %[1]sProps {
+  title?: string
+}
+
+export default function %[1]s({ title = %[2]q }: %[1]sProps) {
+  return (
+    <div className="rounded-lg border border-gray-200 p-4 shadow-sm">
+      <h2 className="text-lg font-semibold">{title}</h2>
+      <p className="text-sm text-gray-600">{%[3]q}</p>
+    </div>
+  )
+}
+`, s.componentName(), s.Name, s.Description)
+}
+
+func generateModuleScaffoldReadme(s ModuleScaffold) string {
+	return fmt.Sprintf(`# %[1]s
+
+%[2]s
+
+A [go-vite](https://github.com/guiperry/go-vite) module.
+
+## Install
+
+From a go-vite project:
+
+`+"```bash"+`
+go-vite install-local path/to/%[1]s
+`+"```"+`
+
+## Contract
+
+go-vite copies this directory to `+"`backend/internal/modules/%[3]s`"+` and applies
+`+"`govite-module.json`"+`:
+
+- `+"`go`"+` adds `+"`require %[3]s`"+` with a `+"`replace`"+` pointing at the copy to
+  `+"`backend/go.mod`"+`, and registers the module in `+"`LoadBuiltinModules`"+` as
+  `+"`manager.Register(%[1]q, %[4]s.New())`"+`.
+- `+"`frontend.components`"+` are copied to `+"`frontend/src/components/%[4]s/`"+`.
+
+`+"`New`"+` must return a value with the methods of the backend `+"`Module`"+` interface:
+
+`+"```go"+`
+type Module interface {
+	Name() string
+	Execute(input map[string]interface{}) (map[string]interface{}, error)
+	Validate(config map[string]interface{}) error
+}
+`+"```"+`
+
+The interface is satisfied structurally; the module must not import the
+backend. Keep `+"`go.mod`"+` free of dependencies the backend doesn't have,
+or add them to the backend as well.
+
+The manifest can also declare routes, pages, environment variables,
+migrations and dependencies on other modules.
+
+## Develop
+
+`+"```bash"+`
+go test ./...
+go-vite publish --registry path/to/registry
+`+"```"+`
+`, s.Name, s.Description, s.Module, s.packageName())
+}
+
+func generateModuleScaffoldGitignore() string {
+	return `*.test
+*.out
+node_modules/
+dist/
+`
+}
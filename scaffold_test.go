@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModuleScaffoldComponentName(t *testing.T) {
+	cases := map[string]string{
+		"csv-export":  "CsvExport",
+		"storage.sql": "StorageSql",
+		"auth":        "Auth",
+		"2fa":         "Module2fa",
+		"PDF_reports": "PDFReports",
+	}
+	for name, expected := range cases {
+		if got := (ModuleScaffold{Name: name}).componentName(); got != expected {
+			t.Fatalf("componentName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestCreateModuleScaffoldRejectsBadNames(t *testing.T) {
+	for _, name := range []string{"", "../escape", "-flag", "a b"} {
+		if err := createModuleScaffold(filepath.Join(t.TempDir(), "m"), ModuleScaffold{Name: name}); err == nil {
+			t.Fatalf("Expected module name %q to be rejected", name)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := createModuleScaffold(dir, ModuleScaffold{Name: "exists"}); err == nil {
+		t.Fatal("Expected an existing directory to be rejected")
+	}
+}
+
+// runGo runs the go command in dir without network access.
+func runGo(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+func TestModuleScaffoldInstallsIntoProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	moduleDir := filepath.Join(t.TempDir(), "csv-export")
+	scaffold := ModuleScaffold{Name: "csv-export", Module: "github.com/acme/csv-export", Description: "Export <tables>"}
+	if err := createModuleScaffold(moduleDir, scaffold); err != nil {
+		t.Fatalf("createModuleScaffold failed: %v", err)
+	}
+
+	for _, file := range []string{"go.mod", "csvexport.go", "csvexport_test.go", manifestFileName, "web/CsvExport.tsx", "README.md"} {
+		if _, err := os.Stat(filepath.Join(moduleDir, file)); err != nil {
+			t.Fatalf("Expected %s in the scaffold", file)
+		}
+	}
+	manifest, err := loadModuleManifest(moduleDir)
+	if err != nil || manifest == nil {
+		t.Fatalf("Expected a valid manifest, got %v", err)
+	}
+
+	// The scaffold's own tests pass
+	runGo(t, moduleDir, "test", "./...")
+
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+	installLocalModule(moduleDir, installOptions{})
+
+	content, _ := os.ReadFile(builtinModulesFile)
+	if !strings.Contains(string(content), `manager.Register("csv-export", csvexport.New())`) {
+		t.Fatalf("Expected the module to be registered, got:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(frontendCompsDir, "csvexport", "CsvExport.tsx")); err != nil {
+		t.Fatal("Expected the example component to be copied")
+	}
+
+	// The generated backend's modules package compiles with the module
+	runGo(t, "backend", "vet", "./internal/modules/")
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	releaseManifestFile = "releases.json"
+	releaseSourceEnvVar = "GOVITE_RELEASE_URL"
+	releaseKeyEnvVar    = "GOVITE_RELEASE_KEY"
+	defaultChannel      = "stable"
+)
+
+// releasePublicKey is the base64 ed25519 key release binaries are signed
+// with. Release builds set it with
+// -ldflags "-X main.releasePublicKey=<key>"; $GOVITE_RELEASE_KEY overrides it.
+var releasePublicKey = ""
+
+// executablePath returns the binary self-update replaces. Tests point it at
+// a copy.
+var executablePath = os.Executable
+
+// ReleaseManifest is the releases.json at the root of a release source. Like
+// a registry, a release source is a directory or a static HTTP location:
+//
+//	releases.json
+//	<version>/go-vite-<os>-<arch>
+type ReleaseManifest struct {
+	Releases []Release `json:"releases"`
+}
+
+// Release is one published version of the CLI. Binaries is keyed by
+// "<os>/<arch>".
+type Release struct {
+	Version  string                   `json:"version"`
+	Channel  string                   `json:"channel"`
+	Binaries map[string]ReleaseBinary `json:"binaries"`
+}
+
+// ReleaseBinary points at a platform binary relative to the release source.
+// Signature is the base64 ed25519 signature of its releaseStatement, since
+// releases.json itself is not signed.
+type ReleaseBinary struct {
+	Path      string `json:"path"`
+	SHA256    string `json:"sha256"`
+	Signature string `json:"signature"`
+}
+
+// onChannel reports whether r is offered on channel. Stable releases are
+// offered on every channel, so beta users still receive newer stable builds.
+func (r Release) onChannel(channel string) bool {
+	c := r.Channel
+	if c == "" {
+		c = defaultChannel
+	}
+	return c == channel || c == defaultChannel
+}
+
+// latest returns the newest release offered on channel.
+func (m *ReleaseManifest) latest(channel string) (*Release, bool) {
+	var best *Release
+	var bestV semver
+	for i := range m.Releases {
+		r := &m.Releases[i]
+		v, err := parseSemver(r.Version)
+		if err != nil || !r.onChannel(channel) {
+			continue
+		}
+		if best == nil || v.Compare(bestV) > 0 {
+			best, bestV = r, v
+		}
+	}
+	return best, best != nil
+}
+
+// releaseSource returns the release source to use: the --source flag if
+// given, otherwise $GOVITE_RELEASE_URL, otherwise the source saved in the CLI
+// config.
+func releaseSource(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if env := os.Getenv(releaseSourceEnvVar); env != "" {
+		return env, nil
+	}
+	if data, err := loadData(); err == nil && data.ReleaseSource != "" {
+		return data.ReleaseSource, nil
+	}
+	return "", fmt.Errorf("no release source configured; use --source or set $%s", releaseSourceEnvVar)
+}
+
+// releaseChannel returns the --channel flag if given, otherwise the channel
+// pinned in the CLI config, otherwise stable.
+func releaseChannel(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if data, err := loadData(); err == nil && data.ReleaseChannel != "" {
+		return data.ReleaseChannel
+	}
+	return defaultChannel
+}
+
+func loadReleaseManifest(source string) (*ReleaseManifest, error) {
+	content, err := fetchRegistryFile(source, releaseManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	m := &ReleaseManifest{}
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("invalid release manifest at %s: %w", source, err)
+	}
+	return m, nil
+}
+
+// checkForUpdate returns the newest release on channel if it is newer than
+// the running version, or nil if the CLI is up to date.
+func checkForUpdate(source, channel string) (*Release, error) {
+	m, err := loadReleaseManifest(source)
+	if err != nil {
+		return nil, err
+	}
+	latest, ok := m.latest(channel)
+	if !ok {
+		return nil, fmt.Errorf("no releases on the %s channel", channel)
+	}
+	current, err := parseSemver(version)
+	if err != nil {
+		return nil, err
+	}
+	latestV, _ := parseSemver(latest.Version)
+	if latestV.Compare(current) <= 0 {
+		return nil, nil
+	}
+	return latest, nil
+}
+
+func releaseKey() (ed25519.PublicKey, error) {
+	encoded := releasePublicKey
+	if env := os.Getenv(releaseKeyEnvVar); env != "" {
+		encoded = env
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("this build has no release signing key; set $%s", releaseKeyEnvVar)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid release signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// releaseStatement is what a release binary's signature covers. It binds the
+// checksum to a version and platform, so a signed binary cannot be offered as
+// another release or for another platform.
+func releaseStatement(version, platform, sha256 string) []byte {
+	return []byte("go-vite " + version + " " + platform + " sha256:" + strings.ToLower(sha256))
+}
+
+// fetchReleaseBinary downloads the binary of r for the running platform and
+// verifies its checksum and signature. It refuses releases that are not newer
+// than the running version.
+func fetchReleaseBinary(source string, r *Release) ([]byte, error) {
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	bin, ok := r.Binaries[platform]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s", r.Version, platform)
+	}
+	key, err := releaseKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(bin.Signature)
+	if err != nil || !ed25519.Verify(key, releaseStatement(r.Version, platform, bin.SHA256), sig) {
+		return nil, fmt.Errorf("invalid signature for %s %s (%s)", r.Version, platform, bin.Path)
+	}
+	current, err := parseSemver(version)
+	if err != nil {
+		return nil, err
+	}
+	if v, err := parseSemver(r.Version); err != nil || v.Compare(current) <= 0 {
+		return nil, fmt.Errorf("release %s is not newer than v%s", r.Version, version)
+	}
+
+	content, err := fetchRegistryFile(source, bin.Path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	if got := hex.EncodeToString(sum[:]); got != strings.ToLower(bin.SHA256) {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", bin.Path, bin.SHA256, got)
+	}
+	return content, nil
+}
+
+// replaceExecutable atomically replaces the file at path with content. The
+// new binary is written next to the old one and renamed over it, so an
+// interrupted update leaves the old binary in place.
+func replaceExecutable(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".go-vite-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+
+	// Windows cannot rename over a running executable, but it can move it
+	// aside first.
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Rename(old, path)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// selfUpdate installs the newest release on channel over the running binary.
+// It returns the installed version, or "" if the CLI is already up to date.
+func selfUpdate(source, channel string) (string, error) {
+	release, err := checkForUpdate(source, channel)
+	if err != nil || release == nil {
+		return "", err
+	}
+	content, err := fetchReleaseBinary(source, release)
+	if err != nil {
+		return "", err
+	}
+	exe, err := executablePath()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if err := replaceExecutable(exe, content); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return release.Version, nil
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("go-vite v%s\n", version)
+	check, _ := cmd.Flags().GetBool("check")
+	if !check {
+		return nil
+	}
+
+	sourceFlag, _ := cmd.Flags().GetString("source")
+	channelFlag, _ := cmd.Flags().GetString("channel")
+	source, err := releaseSource(sourceFlag)
+	if err != nil {
+		return err
+	}
+	channel := releaseChannel(channelFlag)
+	release, err := checkForUpdate(source, channel)
+	if err != nil {
+		return err
+	}
+	if release == nil {
+		fmt.Printf("✅ Up to date on the %s channel\n", channel)
+		return nil
+	}
+	fmt.Printf("⬆️  go-vite v%s is available on the %s channel. Run 'go-vite self-update' to upgrade.\n", release.Version, channel)
+	return nil
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	sourceFlag, _ := cmd.Flags().GetString("source")
+	channelFlag, _ := cmd.Flags().GetString("channel")
+	source, err := releaseSource(sourceFlag)
+	if err != nil {
+		return err
+	}
+	channel := releaseChannel(channelFlag)
+
+	// Passing --source or --channel pins it for later checks and updates.
+	if sourceFlag != "" || channelFlag != "" {
+		data, err := loadData()
+		if err != nil {
+			return err
+		}
+		if sourceFlag != "" {
+			data.ReleaseSource = sourceFlag
+		}
+		if channelFlag != "" {
+			data.ReleaseChannel = channelFlag
+		}
+		if err := saveData(data); err != nil {
+			return err
+		}
+	}
+
+	installed, err := selfUpdate(source, channel)
+	if err != nil {
+		return err
+	}
+	if installed == "" {
+		fmt.Printf("✅ go-vite v%s is up to date on the %s channel\n", version, channel)
+		return nil
+	}
+	fmt.Printf("✅ Updated go-vite v%s -> v%s (%s)\n", version, installed, channel)
+	return nil
+}
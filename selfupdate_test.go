@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newReleaseSource writes a release source with one binary per release
+// and returns its directory. The binary of each release is its version.
+func newReleaseSource(t *testing.T, priv ed25519.PrivateKey, releases map[string]string) string {
+	dir := t.TempDir()
+	manifest := ReleaseManifest{}
+	for v, channel := range releases {
+		content := []byte("go-vite " + v)
+		rel := v + "/go-vite-" + runtime.GOOS + "-" + runtime.GOARCH
+		os.MkdirAll(filepath.Join(dir, v), 0755)
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), content, 0755); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(content)
+		platform := runtime.GOOS + "/" + runtime.GOARCH
+		statement := releaseStatement(v, platform, hex.EncodeToString(sum[:]))
+		manifest.Releases = append(manifest.Releases, Release{
+			Version: v,
+			Channel: channel,
+			Binaries: map[string]ReleaseBinary{
+				platform: {
+					Path:      rel,
+					SHA256:    hex.EncodeToString(sum[:]),
+					Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, statement)),
+				},
+			},
+		})
+	}
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(filepath.Join(dir, releaseManifestFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// setupSelfUpdate points the release key and executable at test values and
+// returns the fake executable.
+func setupSelfUpdate(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	exe := filepath.Join(t.TempDir(), "go-vite")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldKey, oldExe, oldVersion := releasePublicKey, executablePath, version
+	releasePublicKey = base64.StdEncoding.EncodeToString(pub)
+	executablePath = func() (string, error) { return exe, nil }
+	version = "1.0.0"
+	t.Cleanup(func() { releasePublicKey, executablePath, version = oldKey, oldExe, oldVersion })
+	return priv, exe
+}
+
+func TestSelfUpdateOverHTTP(t *testing.T) {
+	priv, exe := setupSelfUpdate(t)
+	source := newReleaseSource(t, priv, map[string]string{"1.1.0": "stable", "1.0.0": "stable", "1.2.0-beta.1": "beta"})
+	server := httptest.NewServer(http.FileServer(http.Dir(source)))
+	defer server.Close()
+
+	installed, err := selfUpdate(server.URL, "stable")
+	if err != nil {
+		t.Fatalf("selfUpdate failed: %v", err)
+	}
+	if installed != "1.1.0" {
+		t.Fatalf("Expected 1.1.0 on the stable channel, got %q", installed)
+	}
+	content, _ := os.ReadFile(exe)
+	if string(content) != "go-vite 1.1.0" {
+		t.Fatalf("Expected the executable to be replaced, got %q", content)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm()&0111 == 0 {
+		t.Fatal("Expected the new binary to be executable")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Fatalf("Expected no temporary files to be left, got %d entries", len(entries))
+	}
+}
+
+func TestSelfUpdateChannels(t *testing.T) {
+	priv, _ := setupSelfUpdate(t)
+	source := newReleaseSource(t, priv, map[string]string{"1.1.0": "stable", "1.2.0-beta.1": "beta"})
+
+	release, err := checkForUpdate(source, "beta")
+	if err != nil || release == nil || release.Version != "1.2.0-beta.1" {
+		t.Fatalf("Expected 1.2.0-beta.1 on the beta channel, got %+v, %v", release, err)
+	}
+
+	// Stable releases reach beta users once they are newer
+	source = newReleaseSource(t, priv, map[string]string{"1.3.0": "stable", "1.2.0-beta.1": "beta"})
+	if release, _ := checkForUpdate(source, "beta"); release == nil || release.Version != "1.3.0" {
+		t.Fatalf("Expected 1.3.0 on the beta channel, got %+v", release)
+	}
+
+	version = "1.3.0"
+	if release, err := checkForUpdate(source, "stable"); err != nil || release != nil {
+		t.Fatalf("Expected to be up to date, got %+v, %v", release, err)
+	}
+}
+
+func TestSelfUpdateRejectsBadBinaries(t *testing.T) {
+	priv, exe := setupSelfUpdate(t)
+	source := newReleaseSource(t, priv, map[string]string{"1.1.0": "stable"})
+	bin := filepath.Join(source, "1.1.0", "go-vite-"+runtime.GOOS+"-"+runtime.GOARCH)
+
+	os.WriteFile(bin, []byte("tampered"), 0755)
+	if _, err := selfUpdate(source, "stable"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected a checksum mismatch, got %v", err)
+	}
+
+	// A matching checksum is not enough without the right signature
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	source = newReleaseSource(t, otherKey, map[string]string{"1.1.0": "stable"})
+	if _, err := selfUpdate(source, "stable"); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Fatalf("Expected an invalid signature, got %v", err)
+	}
+
+	content, _ := os.ReadFile(exe)
+	if string(content) != "old" {
+		t.Fatalf("Expected the executable to be untouched, got %q", content)
+	}
+}
+
+func TestSelfUpdateRejectsReplayedSignatures(t *testing.T) {
+	priv, exe := setupSelfUpdate(t)
+	source := newReleaseSource(t, priv, map[string]string{"0.9.0": "stable"})
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+
+	// An old signed binary relabelled as a newer release
+	manifest, _ := loadReleaseManifest(source)
+	old := manifest.Releases[0]
+	replayed := Release{Version: "1.1.0", Binaries: old.Binaries}
+	if _, err := fetchReleaseBinary(source, &replayed); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Fatalf("Expected the relabelled binary to be rejected, got %v", err)
+	}
+
+	// A binary signed for another platform
+	bin := old.Binaries[platform]
+	bin.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, releaseStatement("1.1.0", "plan9/arm", bin.SHA256)))
+	other := Release{Version: "1.1.0", Binaries: map[string]ReleaseBinary{platform: bin}}
+	if _, err := fetchReleaseBinary(source, &other); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Fatalf("Expected a binary signed for another platform to be rejected, got %v", err)
+	}
+
+	// A correctly signed downgrade
+	if _, err := fetchReleaseBinary(source, &old); err == nil || !strings.Contains(err.Error(), "not newer than v1.0.0") {
+		t.Fatalf("Expected the downgrade to be refused, got %v", err)
+	}
+
+	content, _ := os.ReadFile(exe)
+	if string(content) != "old" {
+		t.Fatalf("Expected the executable to be untouched, got %q", content)
+	}
+}
+
+func TestSelfUpdatePinsSourceAndChannel(t *testing.T) {
+	priv, exe := setupSelfUpdate(t)
+	source := newReleaseSource(t, priv, map[string]string{"1.1.0": "stable", "1.2.0-beta.1": "beta"})
+
+	selfUpdateCmd.Flags().Set("source", source)
+	selfUpdateCmd.Flags().Set("channel", "beta")
+	defer selfUpdateCmd.Flags().Set("source", "")
+	defer selfUpdateCmd.Flags().Set("channel", "")
+	if err := runSelfUpdate(selfUpdateCmd, nil); err != nil {
+		t.Fatalf("self-update failed: %v", err)
+	}
+	if content, _ := os.ReadFile(exe); string(content) != "go-vite 1.2.0-beta.1" {
+		t.Fatalf("Expected the beta binary, got %q", content)
+	}
+
+	if got, _ := releaseSource(""); got != source {
+		t.Fatalf("Expected the source to be saved, got %q", got)
+	}
+	if got := releaseChannel(""); got != "beta" {
+		t.Fatalf("Expected the beta channel to be pinned, got %q", got)
+	}
+}
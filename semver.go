@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var preReleasePattern = regexp.MustCompile(`^[0-9A-Za-z.-]+$`)
+
+// semver is a semantic version. parts records how many numeric components
+// were written, so that "1.2" can act as a prefix matching any 1.2.x.
+type semver struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string
+	parts int
+}
+
+func parseSemver(s string) (semver, error) {
+	v := semver{}
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if str == "" {
+		return v, fmt.Errorf("empty version")
+	}
+	if i := strings.IndexByte(str, '+'); i != -1 {
+		str = str[:i]
+	}
+	if i := strings.IndexByte(str, '-'); i != -1 {
+		v.Pre = str[i+1:]
+		str = str[:i]
+		if !preReleasePattern.MatchString(v.Pre) {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+	}
+
+	fields := strings.Split(str, ".")
+	if len(fields) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	v.parts = len(fields)
+	return v, nil
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1. A pre-release sorts before its release.
+func (v semver) Compare(o semver) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	case v.Pre < o.Pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// hasPrefix reports whether v matches a partial version such as "1" or "1.2".
+func (v semver) hasPrefix(p semver) bool {
+	if v.Major != p.Major || (p.parts > 1 && v.Minor != p.Minor) || (p.parts > 2 && v.Patch != p.Patch) {
+		return false
+	}
+	if p.parts < 3 {
+		return v.Pre == ""
+	}
+	return v.Pre == p.Pre
+}
+
+// comparator is one condition of a version range, such as ">=1.2.0".
+type comparator struct {
+	// op is "=", "<", "<=", ">", ">=" or "prefix" for partial versions.
+	op string
+	v  semver
+}
+
+func (c comparator) matches(v semver) bool {
+	if c.op == "prefix" {
+		return v.hasPrefix(c.v)
+	}
+	d := v.Compare(c.v)
+	switch c.op {
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	default:
+		return d == 0
+	}
+}
+
+var rangeOperatorSpace = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+
+// parseRange parses a version range: alternatives separated by "||", each a
+// space-separated list of conditions that must all hold. A condition is an
+// exact or partial version ("1.2.3", "1.2", "1.x"), "*", a caret or tilde
+// range ("^1.2.0", "~1.2.0") or a comparison (">=1.2.0", "<2"). An empty
+// range or "latest" matches any release.
+func parseRange(constraint string) ([][]comparator, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || constraint == "latest" {
+		return [][]comparator{nil}, nil
+	}
+	constraint = rangeOperatorSpace.ReplaceAllString(constraint, "$1")
+
+	var sets [][]comparator
+	for _, alternative := range strings.Split(constraint, "||") {
+		var set []comparator
+		for _, field := range strings.Fields(alternative) {
+			conditions, err := parseCondition(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %w", constraint, err)
+			}
+			set = append(set, conditions...)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// parsePartialVersion parses a version that may end in ".x" or ".*"
+// wildcards, which are treated as missing components.
+func parsePartialVersion(s string) (semver, error) {
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		for strings.HasSuffix(s, wildcard) {
+			s = strings.TrimSuffix(s, wildcard)
+		}
+	}
+	return parseSemver(s)
+}
+
+// nextVersion returns the smallest version above every version that has v
+// as a prefix, e.g. 1.3.0 for "1.2".
+func nextVersion(v semver) semver {
+	switch v.parts {
+	case 1:
+		return semver{Major: v.Major + 1, parts: 3}
+	case 2:
+		return semver{Major: v.Major, Minor: v.Minor + 1, parts: 3}
+	default:
+		return semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, parts: 3}
+	}
+}
+
+func parseCondition(field string) ([]comparator, error) {
+	if field == "*" || field == "x" || field == "X" {
+		return nil, nil
+	}
+
+	switch field[0] {
+	case '^', '~':
+		v, err := parsePartialVersion(field[1:])
+		if err != nil {
+			return nil, err
+		}
+		lower := semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Pre: v.Pre, parts: 3}
+		var upper semver
+		switch {
+		case field[0] == '~' && v.parts > 1:
+			upper = semver{Major: v.Major, Minor: v.Minor + 1, parts: 3}
+		case field[0] == '~' || v.Major > 0 || v.parts == 1:
+			upper = semver{Major: v.Major + 1, parts: 3}
+		case v.Minor > 0 || v.parts == 2:
+			upper = semver{Minor: v.Minor + 1, parts: 3}
+		default:
+			upper = semver{Patch: v.Patch + 1, parts: 3}
+		}
+		return []comparator{{">=", lower}, {"<", upper}}, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(field, op) {
+			continue
+		}
+		v, err := parsePartialVersion(field[len(op):])
+		if err != nil {
+			return nil, err
+		}
+		if v.parts == 3 {
+			return []comparator{{op, v}}, nil
+		}
+		// Partial versions compare as the whole range they cover
+		switch op {
+		case ">":
+			return []comparator{{">=", nextVersion(v)}}, nil
+		case "<=":
+			return []comparator{{"<", nextVersion(v)}}, nil
+		case "=":
+			return []comparator{{"prefix", v}}, nil
+		}
+		v.parts = 3
+		return []comparator{{op, v}}, nil
+	}
+
+	v, err := parsePartialVersion(field)
+	if err != nil {
+		return nil, err
+	}
+	if v.parts == 3 {
+		return []comparator{{"=", v}}, nil
+	}
+	return []comparator{{"prefix", v}}, nil
+}
+
+// setMatches reports whether v satisfies every condition of set. Pre-releases
+// only match when a condition names a pre-release of the same version, so
+// "^1.2.0" never selects 1.3.0-beta.
+func setMatches(set []comparator, v semver) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if v.Pre == "" {
+		return true
+	}
+	for _, c := range set {
+		if c.v.Pre != "" && c.v.Major == v.Major && c.v.Minor == v.Minor && c.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRange reports whether constraint is a valid version range.
+func validateRange(constraint string) error {
+	_, err := parseRange(constraint)
+	return err
+}
+
+// versionMatches reports whether version satisfies constraint, a version
+// range as accepted by parseRange.
+func versionMatches(version, constraint string) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false
+	}
+	sets, err := parseRange(constraint)
+	if err != nil {
+		return false
+	}
+	for _, set := range sets {
+		if setMatches(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// latestMatching returns the highest version satisfying constraint.
+func latestMatching(versions []string, constraint string) (string, bool) {
+	var best string
+	var bestV semver
+	for _, version := range versions {
+		if !versionMatches(version, constraint) {
+			continue
+		}
+		v, _ := parseSemver(version)
+		if best == "" || v.Compare(bestV) > 0 {
+			best, bestV = version, v
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	v, err := parseSemver("v1.2.3-beta.1+build")
+	if err != nil {
+		t.Fatalf("parseSemver failed: %v", err)
+	}
+	if v.String() != "1.2.3-beta.1" {
+		t.Fatalf("Expected 1.2.3-beta.1, got %s", v)
+	}
+
+	for _, invalid := range []string{"", "1.2.3.4", "a.b", "1.-2", "1.0.0-../x"} {
+		if _, err := parseSemver(invalid); err == nil {
+			t.Fatalf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	ordered := []string{"0.9.0", "1.0.0-alpha", "1.0.0-beta", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := parseSemver(ordered[i-1])
+		b, _ := parseSemver(ordered[i])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Fatalf("Expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestLatestMatching(t *testing.T) {
+	versions := []string{"1.1.0", "1.2.0", "1.2.5", "1.3.0-rc.1", "2.0.0"}
+	cases := map[string]string{
+		"":      "2.0.0",
+		"1":     "1.2.5",
+		"1.2":   "1.2.5",
+		"1.2.0": "1.2.0",
+		"1.3":   "",
+	}
+	for constraint, expected := range cases {
+		got, ok := latestMatching(versions, constraint)
+		if got != expected || ok != (expected != "") {
+			t.Fatalf("latestMatching(%q) = %q, want %q", constraint, got, expected)
+		}
+	}
+}
+
+func TestVersionMatchesRanges(t *testing.T) {
+	cases := []struct {
+		version    string
+		constraint string
+		matches    bool
+	}{
+		{"1.4.2", "^1.2.0", true},
+		{"2.0.0", "^1.2.0", false},
+		{"1.1.9", "^1.2.0", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9.0", "~1", true},
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.5.0", ">= 1.2 < 2", true},
+		{"1.3.0", ">1.2", true},
+		{"1.2.7", ">1.2", false},
+		{"1.2.7", "<=1.2", true},
+		{"3.1.0", "^1.0.0 || ^3.0.0", true},
+		{"2.1.0", "^1.0.0 || ^3.0.0", false},
+		{"1.7.0", "1.x", true},
+		{"5.0.0", "*", true},
+		{"1.3.0-beta", "^1.2.0", false},
+		{"1.3.0-beta", ">=1.3.0-alpha <2", true},
+	}
+	for _, c := range cases {
+		if got := versionMatches(c.version, c.constraint); got != c.matches {
+			t.Fatalf("versionMatches(%q, %q) = %v, expected %v", c.version, c.constraint, got, c.matches)
+		}
+	}
+
+	for _, invalid := range []string{"^", ">=abc", "1.2 || ~x.y"} {
+		if err := validateRange(invalid); err == nil {
+			t.Fatalf("Expected range %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const signatureFileName = "govite-module.sig"
+
+// Signature policies decide what install-local and import-module do with a
+// module whose signature is missing or does not verify.
+const (
+	policyOff     = "off"
+	policyWarn    = "warn"
+	policyEnforce = "enforce"
+)
+
+// ModuleSignature is govite-module.sig at the root of a signed module. It is
+// an ed25519 signature over the module's content hash, which covers every
+// file the module copy keeps except the signature itself.
+type ModuleSignature struct {
+	Hash      string `json:"hash"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"`
+}
+
+// keyID returns a short fingerprint of a public key.
+func keyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "ed25519:" + hex.EncodeToString(sum[:8])
+}
+
+func decodePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// readPrivateKey reads a base64 ed25519 private key written by module keygen.
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%s is not an ed25519 private key", path)
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// moduleContentHash is the hash a module signature covers.
+func moduleContentHash(dir string) (string, error) {
+	return hashModuleTreeExcept(dir, signatureFileName)
+}
+
+// signModule writes govite-module.sig for the module at dir and returns it.
+func signModule(dir string, key ed25519.PrivateKey) (*ModuleSignature, error) {
+	hash, err := moduleContentHash(dir)
+	if err != nil {
+		return nil, err
+	}
+	sig := &ModuleSignature{
+		Hash:      hash,
+		KeyID:     keyID(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(hash))),
+	}
+	content, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return sig, os.WriteFile(filepath.Join(dir, signatureFileName), append(content, '\n'), 0644)
+}
+
+// verifyModuleSignature checks the module at dir against the trusted keys,
+// keyed by name, and returns the name of the key that signed it.
+func verifyModuleSignature(dir string, trusted map[string]string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, signatureFileName))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("module is not signed")
+	}
+	if err != nil {
+		return "", err
+	}
+	sig := &ModuleSignature{}
+	if err := json.Unmarshal(content, sig); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", signatureFileName, err)
+	}
+
+	hash, err := moduleContentHash(dir)
+	if err != nil {
+		return "", err
+	}
+	if hash != sig.Hash {
+		return "", fmt.Errorf("module content does not match its signature (signed %s, found %s)", sig.Hash, hash)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", signatureFileName, err)
+	}
+	for _, name := range sortedKeys(trusted) {
+		pub, err := decodePublicKey(trusted[name])
+		if err != nil || keyID(pub) != sig.KeyID {
+			continue
+		}
+		if !ed25519.Verify(pub, []byte(sig.Hash), signature) {
+			return "", fmt.Errorf("invalid signature by trusted key %s", name)
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("module is signed by untrusted key %s", sig.KeyID)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkModuleSignature applies the configured signature policy to a module
+// about to be installed. Under warn problems are printed; under enforce they
+// are returned.
+func checkModuleSignature(dir, moduleName string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	policy := data.SignaturePolicy
+	if policy == "" || policy == policyOff {
+		return nil
+	}
+
+	signer, err := verifyModuleSignature(dir, data.TrustedKeys)
+	switch {
+	case err == nil:
+		fmt.Printf("🔏 Module '%s' is signed by %s\n", moduleName, signer)
+	case policy == policyEnforce:
+		return fmt.Errorf("refusing to install '%s': %v", moduleName, err)
+	default:
+		fmt.Printf("⚠️  Warning: module '%s': %v\n", moduleName, err)
+	}
+	return nil
+}
+
+func runModuleKeygen(cmd *cobra.Command, args []string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	keyFile, pubFile := args[0]+".key", args[0]+".pub"
+	if _, err := os.Stat(keyFile); err == nil {
+		return fmt.Errorf("%s already exists", keyFile)
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(priv)+"\n"), 0600); err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(pub)
+	if err := os.WriteFile(pubFile, []byte(encoded+"\n"), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %s and %s (%s)\n", keyFile, pubFile, keyID(pub))
+	fmt.Printf("\nTrust it with:\n   go-vite module trust %s %s\n", filepath.Base(args[0]), encoded)
+	return nil
+}
+
+func runModuleSign(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	keyFile, _ := cmd.Flags().GetString("key")
+	key, err := readPrivateKey(keyFile)
+	if err != nil {
+		return err
+	}
+	sig, err := signModule(dir, key)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Signed %s with %s\n", sig.Hash, sig.KeyID)
+	return nil
+}
+
+func runModuleTrust(cmd *cobra.Command, args []string) error {
+	pub, err := decodePublicKey(args[1])
+	if err != nil {
+		return err
+	}
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	if data.TrustedKeys == nil {
+		data.TrustedKeys = make(map[string]string)
+	}
+	data.TrustedKeys[args[0]] = base64.StdEncoding.EncodeToString(pub)
+	if err := saveData(data); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Trusted %s (%s)\n", args[0], keyID(pub))
+	return nil
+}
+
+func runModuleUntrust(cmd *cobra.Command, args []string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	if _, ok := data.TrustedKeys[args[0]]; !ok {
+		return fmt.Errorf("key %s is not trusted", args[0])
+	}
+	delete(data.TrustedKeys, args[0])
+	return saveData(data)
+}
+
+func runModulePolicy(cmd *cobra.Command, args []string) error {
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		policy := data.SignaturePolicy
+		if policy == "" {
+			policy = policyOff
+		}
+		fmt.Printf("Signature policy: %s\n", policy)
+		for _, name := range sortedKeys(data.TrustedKeys) {
+			if pub, err := decodePublicKey(data.TrustedKeys[name]); err == nil {
+				fmt.Printf("  %s (%s)\n", name, keyID(pub))
+			}
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case policyOff, policyWarn, policyEnforce:
+	default:
+		return fmt.Errorf("unknown policy %q (expected off, warn or enforce)", args[0])
+	}
+	if args[0] == policyEnforce && len(data.TrustedKeys) == 0 {
+		fmt.Println("⚠️  Warning: no keys are trusted, so every module will be refused")
+	}
+	data.SignaturePolicy = args[0]
+	return saveData(data)
+}
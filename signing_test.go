@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupSigning trusts a new key under the name "platform" with the given
+// policy and returns its private key.
+func setupSigning(t *testing.T, policy string) ed25519.PrivateKey {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	data, _ := loadData()
+	data.TrustedKeys = map[string]string{"platform": base64.StdEncoding.EncodeToString(pub)}
+	data.SignaturePolicy = policy
+	if err := saveData(data); err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func TestSignAndVerifyModule(t *testing.T) {
+	priv := setupSigning(t, policyEnforce)
+	dir := t.TempDir()
+	writeTestNodeModule(t, dir, "widgets", "1.0.0")
+
+	if _, err := signModule(dir, priv); err != nil {
+		t.Fatalf("signModule failed: %v", err)
+	}
+	data, _ := loadData()
+	signer, err := verifyModuleSignature(dir, data.TrustedKeys)
+	if err != nil || signer != "platform" {
+		t.Fatalf("Expected a signature by platform, got %q, %v", signer, err)
+	}
+
+	// Ignored paths are not part of the signed content
+	os.WriteFile(filepath.Join(dir, "node_modules", "left-pad", "index.js"), []byte("x"), 0644)
+	if _, err := verifyModuleSignature(dir, data.TrustedKeys); err != nil {
+		t.Fatalf("Expected ignored files not to affect the signature, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "index.js"), []byte("tampered"), 0644)
+	if _, err := verifyModuleSignature(dir, data.TrustedKeys); err == nil || !strings.Contains(err.Error(), "does not match its signature") {
+		t.Fatalf("Expected tampering to be detected, got %v", err)
+	}
+}
+
+func TestVerifyModuleSignatureUntrustedKey(t *testing.T) {
+	setupSigning(t, policyEnforce)
+	_, other, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	writeTestNodeModule(t, dir, "widgets", "1.0.0")
+	signModule(dir, other)
+
+	data, _ := loadData()
+	if _, err := verifyModuleSignature(dir, data.TrustedKeys); err == nil || !strings.Contains(err.Error(), "untrusted key") {
+		t.Fatalf("Expected an untrusted key error, got %v", err)
+	}
+}
+
+func TestCheckModuleSignaturePolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeTestNodeModule(t, dir, "widgets", "1.0.0")
+
+	setupSigning(t, policyOff)
+	if err := checkModuleSignature(dir, "widgets"); err != nil {
+		t.Fatalf("Expected unsigned modules to pass with policy off, got %v", err)
+	}
+
+	setupSigning(t, policyWarn)
+	if err := checkModuleSignature(dir, "widgets"); err != nil {
+		t.Fatalf("Expected unsigned modules to pass with policy warn, got %v", err)
+	}
+
+	priv := setupSigning(t, policyEnforce)
+	if err := checkModuleSignature(dir, "widgets"); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("Expected unsigned modules to be refused, got %v", err)
+	}
+	signModule(dir, priv)
+	if err := checkModuleSignature(dir, "widgets"); err != nil {
+		t.Fatalf("Expected a signed module to pass, got %v", err)
+	}
+}
+
+func TestModuleKeygenAndSignCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	keyBase := filepath.Join(t.TempDir(), "platform")
+	if err := runModuleKeygen(moduleKeygenCmd, []string{keyBase}); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if info, _ := os.Stat(keyBase + ".key"); info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected the private key to be 0600, got %v", info.Mode().Perm())
+	}
+	pub, _ := os.ReadFile(keyBase + ".pub")
+	if err := runModuleTrust(moduleTrustCmd, []string{"platform", string(pub)}); err != nil {
+		t.Fatalf("trust failed: %v", err)
+	}
+	if err := runModulePolicy(modulePolicyCmd, []string{"bogus"}); err == nil {
+		t.Fatal("Expected an unknown policy to be rejected")
+	}
+	if err := runModulePolicy(modulePolicyCmd, []string{policyEnforce}); err != nil {
+		t.Fatalf("policy failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeTestNodeModule(t, dir, "widgets", "1.0.0")
+	moduleSignCmd.Flags().Set("key", keyBase+".key")
+	defer moduleSignCmd.Flags().Set("key", "")
+	if err := runModuleSign(moduleSignCmd, []string{dir}); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if err := checkModuleSignature(dir, "widgets"); err != nil {
+		t.Fatalf("Expected the signed module to be accepted, got %v", err)
+	}
+
+	if err := runModuleUntrust(moduleUntrustCmd, []string{"platform"}); err != nil {
+		t.Fatalf("untrust failed: %v", err)
+	}
+	if err := checkModuleSignature(dir, "widgets"); err == nil {
+		t.Fatal("Expected the module to be refused once its key is untrusted")
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// uninstallOptions controls how uninstall removes a copied module.
+type uninstallOptions struct {
+	// Yes skips the confirmation prompt.
+	Yes bool
+	// Force removes the module even if other modules depend on it.
+	Force bool
+}
+
+// confirmInput is where confirmation answers are read from.
+var confirmInput io.Reader = os.Stdin
+
+// confirm asks a yes/no question and defaults to no.
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// findLocalModule reports whether module was copied into the current project
+// by install-local, import-module or a registry install, and returns the
+// source it was installed from.
+func findLocalModule(module string) (string, bool) {
+	if lock, err := loadLockfile(); err == nil {
+		if entry := lock.find(module); entry != nil {
+			return entry.Source, true
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	data, err := loadData()
+	if err != nil {
+		return "", false
+	}
+	for _, m := range data.InstalledModules[wd] {
+		for _, source := range localModuleSources {
+			if m == source+":"+module {
+				return source, true
+			}
+		}
+	}
+	return "", false
+}
+
+// localUninstall is everything needed to remove a copied module from the
+// project. Manifest is nil when the module's files are already gone.
+type localUninstall struct {
+	Name     string
+	Source   string
+	Type     ProjectType
+	Path     string
+	Manifest *ModuleManifest
+}
+
+func planLocalUninstall(moduleName, source string) (*localUninstall, error) {
+	plan := &localUninstall{Name: moduleName, Source: source, Type: Unknown}
+
+	// The path is always derived from the name, never read from the lock,
+	// so uninstalling cannot delete anything outside the module directories
+	lock, err := loadLockfile()
+	if err != nil {
+		return nil, err
+	}
+	if entry := lock.find(moduleName); entry != nil {
+		plan.Type = parseLockModuleType(entry.Type)
+		if plan.Path, err = moduleDestination(moduleName, plan.Type); err != nil {
+			return nil, err
+		}
+	}
+	if plan.Path == "" {
+		for _, moduleType := range []ProjectType{GoProject, NodeProject} {
+			modulePath, err := moduleDestination(moduleName, moduleType)
+			if err != nil {
+				return nil, err
+			}
+			if detectLocalModuleType(modulePath) == moduleType {
+				plan.Type = moduleType
+				plan.Path = modulePath
+				break
+			}
+		}
+	}
+
+	if dirExists(plan.Path) {
+		if plan.Type == Unknown {
+			plan.Type = detectLocalModuleType(plan.Path)
+		}
+		manifest, err := loadModuleManifest(plan.Path)
+		if err != nil {
+			return nil, err
+		}
+		plan.Manifest = manifest
+	}
+	return plan, nil
+}
+
+func fileContains(path, substr string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), substr)
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// frontendModulePath is the path vite.config.js aliases use for a module
+// installed at modulePath.
+func frontendModulePath(modulePath string) string {
+	rel, err := filepath.Rel(frontendDir, modulePath)
+	if err != nil {
+		return ""
+	}
+	return "'./" + filepath.ToSlash(rel) + "'"
+}
+
+// describe lists what run will delete or edit.
+func (p *localUninstall) describe() []string {
+	var steps []string
+	if dirExists(p.Path) {
+		steps = append(steps, fmt.Sprintf("delete %s/", filepath.ToSlash(p.Path)))
+	}
+
+	if p.Type == NodeProject {
+		if fileContains(packageJSONFile, fmt.Sprintf("%q:", p.Name)) {
+			steps = append(steps, fmt.Sprintf("remove dependency %s from %s", p.Name, packageJSONFile))
+		}
+		if p.Path != "" && fileContains(viteConfigFile, frontendModulePath(p.Path)) {
+			steps = append(steps, fmt.Sprintf("remove aliases for the module from %s", viteConfigFile))
+		}
+	}
+	if fileContains(builtinModulesFile, fmt.Sprintf("manager.Register(%q,", p.Name)) ||
+		(p.Manifest != nil && p.Manifest.Go != nil && fileContains(builtinModulesFile, p.Manifest.goBinding(p.Name).registrationLine())) {
+		steps = append(steps, fmt.Sprintf("remove registration from %s", builtinModulesFile))
+	}
+	if p.Manifest != nil && len(p.Manifest.Routes) > 0 {
+		steps = append(steps, fmt.Sprintf("remove %d routes from %s", len(p.Manifest.Routes), routesFile))
+	}
+	if p.Type == GoProject && fileContains(backendGoModFile, p.Name+" =>") {
+		steps = append(steps, fmt.Sprintf("drop require/replace %s from %s", p.Name, backendGoModFile))
+	}
+
+	dirName := goAlias(p.Name)
+	for _, dir := range []string{
+		filepath.Join(frontendPagesDir, dirName),
+		filepath.Join(frontendCompsDir, dirName),
+		filepath.Join(migrationsDir, dirName),
+	} {
+		if dirExists(dir) {
+			steps = append(steps, fmt.Sprintf("delete %s/", filepath.ToSlash(dir)))
+		}
+	}
+	begin, _ := envBlockMarkers(p.Name)
+	for _, path := range []string{envExampleFile, ".env"} {
+		if fileContains(path, begin+"\n") {
+			steps = append(steps, fmt.Sprintf("remove %s variables from %s", p.Name, path))
+		}
+	}
+
+	if fileContains(lockFileName, fmt.Sprintf("%q", p.Name)) {
+		steps = append(steps, fmt.Sprintf("remove %s from %s", p.Name, lockFileName))
+	}
+	steps = append(steps, fmt.Sprintf("forget %s:%s in go-vite state", p.Source, p.Name))
+	return steps
+}
+
+// run removes the module. Every step tolerates work that was already undone
+// by hand, so a half-removed module can still be cleaned up.
+func (p *localUninstall) run() error {
+	if p.Manifest != nil {
+		if err := unapplyModuleManifest(p.Manifest, p.Name, p.Type, p.Path); err != nil {
+			return fmt.Errorf("failed to revert %s: %w", manifestFileName, err)
+		}
+	}
+
+	if p.Type == NodeProject {
+		if _, err := os.Stat(packageJSONFile); err == nil {
+			if err := editPackageDependency(p.Name, ""); err != nil {
+				return fmt.Errorf("failed to update %s: %w", packageJSONFile, err)
+			}
+		}
+		if p.Path != "" {
+			if err := removeLinesContaining(viteConfigFile, "path.resolve(__dirname, "+frontendModulePath(p.Path)+")"); err != nil {
+				return fmt.Errorf("failed to update %s: %w", viteConfigFile, err)
+			}
+		}
+	}
+
+	if err := removeBuiltinRegistration(p.Name); err != nil {
+		return fmt.Errorf("failed to update %s: %w", builtinModulesFile, err)
+	}
+
+	if p.Type == GoProject && fileContains(backendGoModFile, p.Name+" =>") {
+		if err := runGoModEdit("-dropreplace="+p.Name, "-droprequire="+p.Name); err != nil {
+			return fmt.Errorf("failed to update %s: %w", backendGoModFile, err)
+		}
+	}
+
+	if p.Path != "" {
+		if err := os.RemoveAll(p.Path); err != nil {
+			return err
+		}
+	}
+
+	if err := removeLockEntry(p.Name); err != nil {
+		return fmt.Errorf("failed to update %s: %w", lockFileName, err)
+	}
+	removeInstalledModule(p.Source + ":" + p.Name)
+	return nil
+}
+
+// uninstallLocalModule removes a copied module and everything its install
+// added to the project, after listing the changes and asking for
+// confirmation. Modules other modules depend on are only removed with Force.
+func uninstallLocalModule(moduleName, source string, opts uninstallOptions) {
+	if lock, err := loadLockfile(); err == nil {
+		if entry := lock.find(moduleName); entry != nil {
+			if names := dependents(lock, *entry); len(names) > 0 {
+				if !opts.Force {
+					fmt.Printf("Error: module '%s' is required by %s. Use --force to uninstall it anyway\n",
+						moduleName, strings.Join(names, ", "))
+					os.Exit(1)
+				}
+				fmt.Printf("Warning: %s depend on '%s' and may stop working\n", strings.Join(names, ", "), moduleName)
+			}
+		}
+	}
+
+	plan, err := planLocalUninstall(moduleName, source)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Uninstalling %s module '%s':\n", source, moduleName)
+	for _, step := range plan.describe() {
+		fmt.Printf("  - %s\n", step)
+	}
+	if !opts.Yes && !confirm("Proceed?") {
+		fmt.Println("Aborted")
+		return
+	}
+
+	if err := plan.run(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Module '%s' uninstalled successfully\n", moduleName)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	defer func(r *os.File) { confirmInput = r }(os.Stdin)
+
+	cases := map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false}
+	for input, expected := range cases {
+		confirmInput = strings.NewReader(input)
+		if got := confirm("Proceed?"); got != expected {
+			t.Fatalf("confirm(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+}
+
+func TestPlanLocalUninstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	installTestNodeModule(t, "widgets")
+
+	plan, err := planLocalUninstall("widgets", "local")
+	if err != nil {
+		t.Fatalf("planLocalUninstall failed: %v", err)
+	}
+	if plan.Type != NodeProject || plan.Path != filepath.Join(frontendModulesDir, "widgets") || plan.Manifest == nil {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+
+	steps := strings.Join(plan.describe(), "\n")
+	for _, expected := range []string{
+		"delete frontend/modules/widgets/",
+		"remove dependency widgets from " + packageJSONFile,
+		"remove aliases for the module from " + viteConfigFile,
+		"remove widgets from " + lockFileName,
+		"forget local:widgets",
+	} {
+		if !strings.Contains(steps, expected) {
+			t.Fatalf("Expected plan to mention %q, got:\n%s", expected, steps)
+		}
+	}
+}
+
+func TestPlanLocalUninstallIgnoresLockPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	installTestNodeModule(t, "widgets")
+
+	precious := t.TempDir()
+	lock := `{"version": 1, "modules": [{"name": "widgets", "type": "node", "source": "local", "path": "` + filepath.ToSlash(precious) + `", "hash": "sha256:00"}]}`
+	os.WriteFile(lockFileName, []byte(lock), 0644)
+	if _, err := planLocalUninstall("widgets", "local"); err == nil {
+		t.Fatal("Expected a lock pointing outside the project to be rejected")
+	}
+
+	os.Remove(lockFileName)
+	for _, name := range []string{"../../outside", "/tmp", "a/../../b"} {
+		if _, err := planLocalUninstall(name, "local"); err == nil {
+			t.Errorf("Expected module name %q to be rejected", name)
+		}
+	}
+}
+
+func TestUninstallLocalModule(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+	defer func(r *os.File) { confirmInput = r }(os.Stdin)
+
+	installTestNodeModule(t, "widgets")
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+
+	confirmInput = strings.NewReader("n\n")
+	uninstallLocalModule("widgets", "local", uninstallOptions{})
+	if _, err := os.Stat(modulePath); err != nil {
+		t.Fatal("Expected the module to be kept when confirmation is declined")
+	}
+
+	confirmInput = strings.NewReader("y\n")
+	uninstallLocalModule("widgets", "local", uninstallOptions{})
+
+	if _, err := os.Stat(modulePath); !os.IsNotExist(err) {
+		t.Fatal("Expected the module tree to be deleted")
+	}
+	if content, _ := os.ReadFile(packageJSONFile); strings.Contains(string(content), `"widgets"`) {
+		t.Fatalf("Expected widgets to be removed from package.json, got: %s", content)
+	}
+	if content, _ := os.ReadFile(viteConfigFile); strings.Contains(string(content), "./modules/widgets") {
+		t.Fatalf("Expected the vite alias to be removed, got: %s", content)
+	}
+	if lock, _ := loadLockfile(); lock.find("widgets") != nil {
+		t.Fatalf("Expected widgets to be removed from %s", lockFileName)
+	}
+	if _, ok := findLocalModule("widgets"); ok {
+		t.Fatal("Expected widgets to be forgotten")
+	}
+}
+
+func TestUninstallLocalModuleAlreadyDeleted(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	installTestNodeModule(t, "widgets")
+	os.RemoveAll(filepath.Join(frontendModulesDir, "widgets"))
+
+	uninstallLocalModule("widgets", "local", uninstallOptions{Yes: true})
+
+	if _, ok := findLocalModule("widgets"); ok {
+		t.Fatal("Expected state to be cleaned up when the tree is already gone")
+	}
+	if content, _ := os.ReadFile(packageJSONFile); strings.Contains(string(content), `"widgets"`) {
+		t.Fatalf("Expected widgets to be removed from package.json, got: %s", content)
+	}
+}
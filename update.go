@@ -0,0 +1,469 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// updateOptions controls how update replaces installed modules.
+type updateOptions struct {
+	// Yes skips the confirmation prompt.
+	Yes bool
+	// NoBuildCheck skips compiling the backend after the update.
+	NoBuildCheck bool
+}
+
+// buildBackend compiles the project's backend. An update that breaks the
+// build is rolled back.
+var buildBackend = func() error {
+	if _, err := os.Stat(backendGoModFile); err != nil {
+		return nil
+	}
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = "backend"
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("backend does not compile:\n%s", output)
+	}
+	return nil
+}
+
+// moduleUpdate is a fetched new version of an installed module.
+type moduleUpdate struct {
+	Entry LockEntry
+	// Source is the fetched module and Opts how to install it so that its
+	// lock entry records the new origin, version and commit.
+	Source string
+	Opts   installOptions
+}
+
+// fetchModuleUpdate fetches the module behind a lock entry again from where
+// it was installed. version picks a registry version range or a git ref; by
+// default a registry install moves to the latest version and a git install
+// follows the ref it was installed from.
+func fetchModuleUpdate(entry LockEntry, version string) (*moduleUpdate, error) {
+	u := &moduleUpdate{Entry: entry, Opts: installOptions{
+		Alias:  entry.Alias,
+		Source: entry.Source,
+		Origin: entry.Origin,
+	}}
+
+	switch entry.Source {
+	case "registry":
+		source, module, v, err := resolveRegistryModule([]string{entry.Origin}, entry.Name, version)
+		if err != nil {
+			return nil, err
+		}
+		if module == nil {
+			return nil, fmt.Errorf("%s is no longer published at %s", entry.Name, entry.Origin)
+		}
+		dir, err := fetchRegistryModule(source, module.Name, v)
+		if err != nil {
+			return nil, err
+		}
+		u.Source, u.Opts.Version = dir, v.Version
+	case "git":
+		src, err := parseGitSource(entry.Origin)
+		if err != nil {
+			return nil, err
+		}
+		if version != "" {
+			src.Ref = version
+		}
+		dir, commit, err := checkoutGitSource(src)
+		if err != nil {
+			return nil, err
+		}
+		u.Source, u.Opts.Origin, u.Opts.Commit = dir, src.String(), commit
+	default:
+		if version != "" {
+			return nil, fmt.Errorf("%s was installed from %s; a version can only be chosen for registry and git modules", entry.Name, entry.Origin)
+		}
+		u.Source = entry.Origin
+	}
+
+	if detectLocalModuleType(u.Source) != parseLockModuleType(entry.Type) {
+		return nil, fmt.Errorf("%s: source %s is not a %s module", entry.Name, u.Source, entry.Type)
+	}
+	return u, nil
+}
+
+// treeDigests returns the content digest of every file the ignore rules keep
+// under dir, keyed by slash-separated relative path.
+func treeDigests(dir string) (map[string]string, error) {
+	digests := make(map[string]string)
+	if !dirExists(dir) {
+		return digests, nil
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = walkModuleTree(root, func(rel string, info os.FileInfo) error {
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(filepath.Join(root, filepath.FromSlash(rel)))
+			if err != nil {
+				return err
+			}
+			digests[rel] = "link " + target
+		case info.Mode().IsRegular():
+			file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+			if err != nil {
+				return err
+			}
+			h := sha256.New()
+			_, err = io.Copy(h, file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			digests[rel] = fmt.Sprintf("%x %v", h.Sum(nil), info.Mode().Perm()&0111 != 0)
+		}
+		return nil
+	})
+	return digests, err
+}
+
+// treeDiff lists the files added, changed and removed going from one tree to
+// another.
+type treeDiff struct {
+	Added, Changed, Removed []string
+}
+
+func (d treeDiff) empty() bool {
+	return len(d.Added)+len(d.Changed)+len(d.Removed) == 0
+}
+
+func diffTrees(from, to string) (treeDiff, error) {
+	var diff treeDiff
+	old, err := treeDigests(from)
+	if err != nil {
+		return diff, err
+	}
+	updated, err := treeDigests(to)
+	if err != nil {
+		return diff, err
+	}
+	for rel, digest := range updated {
+		if previous, ok := old[rel]; !ok {
+			diff.Added = append(diff.Added, rel)
+		} else if previous != digest {
+			diff.Changed = append(diff.Changed, rel)
+		}
+	}
+	for rel := range old {
+		if _, ok := updated[rel]; !ok {
+			diff.Removed = append(diff.Removed, rel)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff, nil
+}
+
+// preservedConfig returns the files under the installed module that match
+// the config patterns of its old or new manifest.
+func preservedConfig(installed string, manifests ...*ModuleManifest) ([]string, error) {
+	var patterns []string
+	for _, m := range manifests {
+		if m != nil {
+			patterns = append(patterns, m.Config...)
+		}
+	}
+	if len(patterns) == 0 || !dirExists(installed) {
+		return nil, nil
+	}
+	root, err := filepath.EvalSymlinks(installed)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	err = walkModuleTree(root, func(rel string, info os.FileInfo) error {
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, rel); ok {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// stageModuleUpdate copies the new version into a staging directory and
+// carries the installed copy's config files over into it. The signature is
+// checked on the new version as published, since the staged copy holds the
+// user's config, which the publisher never signed.
+func stageModuleUpdate(u *moduleUpdate, staging string) error {
+	if err := checkModuleSignature(u.Source, u.Entry.Name); err != nil {
+		return err
+	}
+	u.Opts.SignatureChecked = true
+	if err := copyTree(u.Source, staging); err != nil {
+		return err
+	}
+	installed := filepath.FromSlash(u.Entry.Path)
+	oldManifest, _ := loadModuleManifest(installed)
+	newManifest, err := loadModuleManifest(staging)
+	if err != nil {
+		return err
+	}
+	files, err := preservedConfig(installed, oldManifest, newManifest)
+	if err != nil {
+		return err
+	}
+	for _, rel := range files {
+		dst := filepath.Join(staging, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(installed, filepath.FromSlash(rel)), dst); err != nil {
+			return err
+		}
+		fmt.Printf("  keeping %s\n", rel)
+	}
+	return nil
+}
+
+// projectSnapshot holds the project files and directories an install edits,
+// so a failed update can put them back.
+type projectSnapshot struct {
+	backup string
+	// files maps a path to its content; nil means it did not exist.
+	files map[string][]byte
+	// dirs maps a directory to its backup location.
+	dirs map[string]string
+}
+
+func takeProjectSnapshot(moduleName, modulePath string) (*projectSnapshot, error) {
+	backup, err := os.MkdirTemp(".", ".govite-update-*")
+	if err != nil {
+		return nil, err
+	}
+	snap := &projectSnapshot{backup: backup, files: make(map[string][]byte), dirs: make(map[string]string)}
+
+	for _, file := range []string{
+		builtinModulesFile, routesFile, backendGoModFile, "backend/go.sum",
+		packageJSONFile, viteConfigFile, envExampleFile, ".env", lockFileName,
+	} {
+		content, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			snap.discard()
+			return nil, err
+		}
+		snap.files[file] = content
+	}
+
+	// The installed copy and the files its manifest copied out are copied
+	// aside whole and moved back on rollback
+	dirName := goAlias(moduleName)
+	for i, dir := range []string{
+		modulePath,
+		filepath.Join(frontendPagesDir, dirName),
+		filepath.Join(frontendCompsDir, dirName),
+		filepath.Join(migrationsDir, dirName),
+	} {
+		if !dirExists(dir) {
+			continue
+		}
+		saved := filepath.Join(backup, fmt.Sprint(i))
+		if err := copyTree(dir, saved); err != nil {
+			snap.discard()
+			return nil, err
+		}
+		snap.dirs[dir] = saved
+	}
+	return snap, nil
+}
+
+// restore puts every snapshotted file and directory back.
+func (s *projectSnapshot) restore() error {
+	for file, content := range s.files {
+		if content == nil {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return err
+		}
+	}
+	for dir, saved := range s.dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(saved, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *projectSnapshot) discard() {
+	os.RemoveAll(s.backup)
+}
+
+// applyModuleUpdate replaces the installed module with the staged update,
+// registering it again, and rolls everything back if that fails or the
+// backend stops compiling.
+func applyModuleUpdate(u *moduleUpdate, staging string, opts updateOptions) error {
+	entry := u.Entry
+	plan, err := planLocalUninstall(entry.Name, entry.Source)
+	if err != nil {
+		return err
+	}
+	// An update keeps the module's data, so uninstall hooks are not run
+	if plan.Manifest != nil {
+		manifest := *plan.Manifest
+		manifest.PreRemove = nil
+		plan.Manifest = &manifest
+	}
+
+	// Uninstalling drops the module's .env block and installing writes the
+	// defaults, so the user's values are carried over like its config files
+	envValues, err := envBlockValues(".env", entry.Name)
+	if err != nil {
+		return err
+	}
+
+	snap, err := takeProjectSnapshot(entry.Name, plan.Path)
+	if err != nil {
+		return err
+	}
+	defer snap.discard()
+
+	update := func() error {
+		if err := plan.run(); err != nil {
+			return err
+		}
+		if err := installLocal(staging, u.Opts); err != nil {
+			return err
+		}
+		if err := setEnvBlockValues(".env", entry.Name, envValues); err != nil {
+			return err
+		}
+		if opts.NoBuildCheck {
+			return nil
+		}
+		return buildBackend()
+	}
+	if err := update(); err != nil {
+		if restoreErr := snap.restore(); restoreErr != nil {
+			return fmt.Errorf("%v; rolling back also failed: %v", err, restoreErr)
+		}
+		saveInstalledModule(entry.Source + ":" + entry.Name)
+		return fmt.Errorf("update of '%s' rolled back: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// updateModule updates one installed module, after showing which files
+// change and asking for confirmation.
+func updateModule(entry LockEntry, version string, opts updateOptions) error {
+	fmt.Printf("Checking %s (%s)\n", entry.Name, entry.Origin)
+	u, err := fetchModuleUpdate(entry, version)
+	if err != nil {
+		return err
+	}
+
+	staging, err := os.MkdirTemp("", "govite-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	staged := filepath.Join(staging, filepath.Base(filepath.FromSlash(entry.Path)))
+	if err := stageModuleUpdate(u, staged); err != nil {
+		return err
+	}
+
+	diff, err := diffTrees(filepath.FromSlash(entry.Path), staged)
+	if err != nil {
+		return err
+	}
+	if diff.empty() {
+		fmt.Printf("✅ %s is up to date\n", entry.Name)
+		return nil
+	}
+
+	from, to := entry.Version, u.Opts.Version
+	if to == "" {
+		to = moduleVersion(staged, parseLockModuleType(entry.Type))
+	}
+	if entry.Commit != "" && u.Opts.Commit != entry.Commit {
+		from, to = from+" ("+shortCommit(entry.Commit)+")", to+" ("+shortCommit(u.Opts.Commit)+")"
+	}
+	fmt.Printf("Updating %s %s -> %s: %d added, %d changed, %d removed\n",
+		entry.Name, from, to, len(diff.Added), len(diff.Changed), len(diff.Removed))
+	for _, rel := range diff.Added {
+		fmt.Printf("  + %s\n", rel)
+	}
+	for _, rel := range diff.Changed {
+		fmt.Printf("  ~ %s\n", rel)
+	}
+	for _, rel := range diff.Removed {
+		fmt.Printf("  - %s\n", rel)
+	}
+	if !opts.Yes && !confirm("Proceed?") {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	if err := applyModuleUpdate(u, staged, opts); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Updated %s\n", entry.Name)
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	noBuildCheck, _ := cmd.Flags().GetBool("no-build-check")
+	opts := updateOptions{Yes: yes, NoBuildCheck: noBuildCheck}
+
+	lock, err := loadLockfile()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		if len(lock.Modules) == 0 {
+			fmt.Printf("No modules recorded in %s\n", lockFileName)
+			return nil
+		}
+		for _, entry := range append([]LockEntry(nil), lock.Modules...) {
+			if err := updateModule(entry, "", opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	name, version := parseModuleRef(args[0])
+	entry := lock.find(name)
+	if entry == nil {
+		return fmt.Errorf("module '%s' is not recorded in %s", name, lockFileName)
+	}
+	return updateModule(*entry, version, opts)
+}
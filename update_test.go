@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubBuildBackend replaces the backend build check for the test.
+func stubBuildBackend(t *testing.T, fn func() error) {
+	old := buildBackend
+	buildBackend = fn
+	t.Cleanup(func() { buildBackend = old })
+}
+
+func TestDiffTrees(t *testing.T) {
+	from, to := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(from, "same.js"), []byte("same"), 0644)
+	os.WriteFile(filepath.Join(to, "same.js"), []byte("same"), 0644)
+	os.WriteFile(filepath.Join(from, "changed.js"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(to, "changed.js"), []byte("b"), 0644)
+	os.WriteFile(filepath.Join(from, "removed.js"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(to, "added.js"), []byte(""), 0644)
+
+	diff, err := diffTrees(from, to)
+	if err != nil {
+		t.Fatalf("diffTrees failed: %v", err)
+	}
+	if fmt.Sprint(diff.Added, diff.Changed, diff.Removed) != "[added.js] [changed.js] [removed.js]" {
+		t.Fatalf("Unexpected diff %+v", diff)
+	}
+}
+
+func TestUpdateLocalModule(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	stubBuildBackend(t, func() error { return nil })
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := installTestNodeModule(t, "widgets")
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+	// The user configures the installed copy
+	os.WriteFile(filepath.Join(modulePath, "config.json"), []byte(`{"theme": "dark"}`), 0644)
+
+	writeTestNodeModule(t, source, "widgets", "1.1.0")
+	os.WriteFile(filepath.Join(source, manifestFileName), []byte(`{"name": "widgets", "version": "1.1.0", "config": ["config.json"]}`), 0644)
+	os.WriteFile(filepath.Join(source, "index.js"), []byte("module.exports = 2\n"), 0644)
+	os.WriteFile(filepath.Join(source, "config.json"), []byte(`{"theme": "light"}`), 0644)
+
+	lock, _ := loadLockfile()
+	if err := updateModule(*lock.find("widgets"), "", updateOptions{Yes: true}); err != nil {
+		t.Fatalf("updateModule failed: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(modulePath, "index.js")); string(content) != "module.exports = 2\n" {
+		t.Fatalf("Expected the new version to be installed, got %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(modulePath, "config.json")); string(content) != `{"theme": "dark"}` {
+		t.Fatalf("Expected the module config to be kept, got %q", content)
+	}
+	lock, _ = loadLockfile()
+	entry := lock.find("widgets")
+	if entry == nil || entry.Version != "1.1.0" || entry.Origin != source {
+		t.Fatalf("Expected the lock to record 1.1.0 from %s, got %+v", source, entry)
+	}
+	if problems := verifyLockfile(lock); len(problems) != 0 {
+		t.Fatalf("Expected the lock to match the update, got %+v", problems)
+	}
+	if content, _ := os.ReadFile(packageJSONFile); !strings.Contains(string(content), `"widgets": "file:./modules/widgets"`) {
+		t.Fatalf("Expected the module to stay registered, got %s", content)
+	}
+	if source, ok := findLocalModule("widgets"); !ok || source != "local" {
+		t.Fatalf("Expected the module to stay in go-vite state, got %q", source)
+	}
+	if matches, _ := filepath.Glob(".govite-update-*"); len(matches) != 0 {
+		t.Fatalf("Expected the snapshot to be removed, got %v", matches)
+	}
+
+	// A second update finds nothing to do
+	if err := updateModule(*entry, "", updateOptions{Yes: true}); err != nil {
+		t.Fatalf("updateModule failed: %v", err)
+	}
+}
+
+func TestUpdateSignedModuleKeepsConfig(t *testing.T) {
+	priv := setupSigning(t, policyEnforce)
+	stubBuildBackend(t, func() error { return nil })
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := t.TempDir()
+	writeTestNodeModule(t, source, "widgets", "1.0.0")
+	os.WriteFile(filepath.Join(source, manifestFileName), []byte(`{"name": "widgets", "version": "1.0.0", "config": ["config.json"]}`), 0644)
+	os.WriteFile(filepath.Join(source, "config.json"), []byte(`{"theme": "light"}`), 0644)
+	if _, err := signModule(source, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := installLocal(source, installOptions{}); err != nil {
+		t.Fatalf("installLocal failed: %v", err)
+	}
+	modulePath := filepath.Join(frontendModulesDir, "widgets")
+	os.WriteFile(filepath.Join(modulePath, "config.json"), []byte(`{"theme": "dark"}`), 0644)
+
+	os.WriteFile(filepath.Join(source, manifestFileName), []byte(`{"name": "widgets", "version": "1.1.0", "config": ["config.json"]}`), 0644)
+	os.WriteFile(filepath.Join(source, "index.js"), []byte("module.exports = 2\n"), 0644)
+	lock, _ := loadLockfile()
+
+	// An unsigned change is refused before anything is staged
+	if err := updateModule(*lock.find("widgets"), "", updateOptions{Yes: true}); err == nil || !strings.Contains(err.Error(), "refusing to install") {
+		t.Fatalf("Expected the unsigned update to be refused, got %v", err)
+	}
+
+	if _, err := signModule(source, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateModule(*lock.find("widgets"), "", updateOptions{Yes: true}); err != nil {
+		t.Fatalf("Expected the signed update to pass with the config kept, got %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(modulePath, "config.json")); string(content) != `{"theme": "dark"}` {
+		t.Fatalf("Expected the module config to be kept, got %q", content)
+	}
+}
+
+func TestUpdateKeepsEnvValues(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	stubBuildBackend(t, func() error { return nil })
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := t.TempDir()
+	writeTestNodeModule(t, source, "widgets", "1.0.0")
+	os.WriteFile(filepath.Join(source, manifestFileName), []byte(`{"name": "widgets", "version": "1.0.0", "env": [{"name": "WIDGETS_THEME", "default": "light"}]}`), 0644)
+	os.WriteFile(".env", []byte("PORT=8080\n"), 0644)
+	if err := installLocal(source, installOptions{}); err != nil {
+		t.Fatalf("installLocal failed: %v", err)
+	}
+	// The user sets the module's variable
+	content, _ := os.ReadFile(".env")
+	os.WriteFile(".env", []byte(strings.Replace(string(content), "WIDGETS_THEME=light", "WIDGETS_THEME=dark", 1)), 0644)
+
+	os.WriteFile(filepath.Join(source, manifestFileName), []byte(`{"name": "widgets", "version": "1.1.0", "env": [{"name": "WIDGETS_THEME", "default": "light"}, {"name": "WIDGETS_SIZE", "default": "10"}]}`), 0644)
+	lock, _ := loadLockfile()
+	if err := updateModule(*lock.find("widgets"), "", updateOptions{Yes: true}); err != nil {
+		t.Fatalf("updateModule failed: %v", err)
+	}
+
+	content, _ = os.ReadFile(".env")
+	if !strings.Contains(string(content), "WIDGETS_THEME=dark\n") {
+		t.Fatalf("Expected the user's value to be kept, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "WIDGETS_SIZE=10\n") {
+		t.Fatalf("Expected the new variable with its default, got:\n%s", content)
+	}
+}
+
+func TestUpdateRegistryModule(t *testing.T) {
+	registry := newTestRegistry(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	stubBuildBackend(t, func() error { return nil })
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	if _, err := installFromRegistry("@ourorg/csv-export@1.1", []string{registry}, installOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	lock, _ := loadLockfile()
+	if err := updateModule(*lock.find("@ourorg/csv-export"), "", updateOptions{Yes: true}); err != nil {
+		t.Fatalf("updateModule failed: %v", err)
+	}
+	lock, _ = loadLockfile()
+	if entry := lock.find("@ourorg/csv-export"); entry == nil || entry.Version != "1.2.0" || entry.Origin != registry {
+		t.Fatalf("Expected 1.2.0 from the registry, got %+v", entry)
+	}
+}
+
+func TestUpdateRollsBackWhenBackendBreaks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	// Compile only the modules package, which needs nothing from the network
+	stubBuildBackend(t, func() error {
+		cmd := exec.Command("go", "vet", "./internal/modules/")
+		cmd.Dir = "backend"
+		cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("backend does not compile:\n%s", output)
+		}
+		return nil
+	})
+
+	moduleDir := filepath.Join(t.TempDir(), "csv-export")
+	if err := createModuleScaffold(moduleDir, ModuleScaffold{Name: "csv-export", Module: "github.com/acme/csv-export"}); err != nil {
+		t.Fatal(err)
+	}
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+	installLocalModule(moduleDir, installOptions{})
+	lockBefore, _ := os.ReadFile(lockFileName)
+	builtinBefore, _ := os.ReadFile(builtinModulesFile)
+
+	// The new version no longer implements the module interface
+	goFile := filepath.Join(moduleDir, "csvexport.go")
+	content, _ := os.ReadFile(goFile)
+	os.WriteFile(goFile, []byte(strings.Replace(string(content), "func (m *Module) Name() string", "func (m *Module) Title() string", 1)), 0644)
+
+	lock, _ := loadLockfile()
+	err := updateModule(*lock.find("github.com/acme/csv-export"), "", updateOptions{Yes: true})
+	if err == nil || !strings.Contains(err.Error(), "rolled back") || !strings.Contains(err.Error(), "does not compile") {
+		t.Fatalf("Expected the update to be rolled back, got %v", err)
+	}
+
+	installed, _ := os.ReadFile(filepath.Join(getModuleDestinationPath("github.com/acme/csv-export", GoProject), "csvexport.go"))
+	if !strings.Contains(string(installed), "func (m *Module) Name() string") {
+		t.Fatal("Expected the old module to be restored")
+	}
+	if lockAfter, _ := os.ReadFile(lockFileName); string(lockAfter) != string(lockBefore) {
+		t.Fatalf("Expected %s to be restored", lockFileName)
+	}
+	if builtinAfter, _ := os.ReadFile(builtinModulesFile); string(builtinAfter) != string(builtinBefore) {
+		t.Fatalf("Expected %s to be restored", builtinModulesFile)
+	}
+	if _, ok := findLocalModule("github.com/acme/csv-export"); !ok {
+		t.Fatal("Expected the module to stay in go-vite state")
+	}
+}
+
+func TestUpdateRollsBackWhenDependencyFails(t *testing.T) {
+	registry := newTestDependencyRegistry(t, map[string]map[string]string{
+		"reports@1.2.0": nil,
+	})
+	priv := setupSigning(t, policyEnforce)
+	stubBuildBackend(t, func() error { return nil })
+	_, cleanup := newTestProject(t)
+	defer cleanup()
+
+	source := t.TempDir()
+	writeTestDependentModule(t, source, "dashboard", "1.0.0", nil)
+	if _, err := signModule(source, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := installLocal(source, installOptions{}); err != nil {
+		t.Fatalf("installLocal failed: %v", err)
+	}
+	lockBefore, _ := os.ReadFile(lockFileName)
+
+	// The new version needs a module the enforced policy refuses
+	writeTestDependentModule(t, source, "dashboard", "1.1.0", map[string]string{"reports": "^1"})
+	if _, err := signModule(source, priv); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(registryEnvVar, registry)
+	lock, _ := loadLockfile()
+	err := updateModule(*lock.find("dashboard"), "", updateOptions{Yes: true})
+	if err == nil || !strings.Contains(err.Error(), "rolled back") || !strings.Contains(err.Error(), "dependency reports") {
+		t.Fatalf("Expected the update to be rolled back, got %v", err)
+	}
+
+	if lockAfter, _ := os.ReadFile(lockFileName); string(lockAfter) != string(lockBefore) {
+		t.Fatalf("Expected %s to be restored", lockFileName)
+	}
+	if _, err := os.Stat(filepath.Join(frontendModulesDir, "dashboard", "index.js")); err != nil {
+		t.Fatalf("Expected the old module to be restored: %v", err)
+	}
+	if matches, _ := filepath.Glob(".govite-update-*"); len(matches) != 0 {
+		t.Fatalf("Expected the snapshot to be removed, got %v", matches)
+	}
+}